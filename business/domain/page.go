@@ -0,0 +1,6 @@
+package domain
+
+import "errors"
+
+// ErrInvalidCursor is returned when a page cursor can't be decoded
+var ErrInvalidCursor = errors.New("invalid page cursor")
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// SeatAccessibilityRules are rules of an org for selling wheelchair spaces
+type SeatAccessibilityRules struct {
+	// AccessibilityPriceNameIDs are ticket types wheelchair spaces may be sold with, empty allows any ticket type
+	AccessibilityPriceNameIDs []string
+	// RequireCompanion requires wheelchair spaces to be linked to a companion seat which is sold with them
+	RequireCompanion bool
+	// ReleaseBeforeEvent releases wheelchair spaces to general sale the given time before the event starts,
+	// zero never releases them. The seat expiry worker stores the release for the event, the seats keep their
+	// wheelchair flag and they're sold as regular seats until a later event of their seating plan starts.
+	ReleaseBeforeEvent time.Duration
+}
+
+// SeatTickets describes tickets requested for seats
+type SeatTickets struct {
+	// PriceNameIDs are ticket types by seat id
+	PriceNameIDs map[string]string
+}
+
+// SeatRuleAccessibilityError is returned when a wheelchair space is requested against the accessibility rules
+type SeatRuleAccessibilityError struct {
+	OrigError error
+	SeatID    string
+}
+
+func (e *SeatRuleAccessibilityError) Error() string {
+	return fmt.Sprintf("%v: %s", e.OrigError, e.SeatID)
+}
+
+func (e *SeatRuleAccessibilityError) Unwrap() error {
+	return e.OrigError
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrNoBestSeats is returned when there are no available seats which could be ordered together
+	ErrNoBestSeats = errors.New("no adjacent available seats found")
+	// ErrSeatScoringPlanNotFound is returned when the seating plan of a seat scoring config has no seats of the org
+	ErrSeatScoringPlanNotFound = errors.New("seating plan of seat scoring config not found")
+)
+
+// SeatScoringConfig configures how best available seats of a seating plan are scored. Scores of the scorers
+// are multiplied by their weights and summed up, lower scores are better and weights of zero disable scorers.
+type SeatScoringConfig struct {
+	// DistanceWeight weights the distance of seats from the reference points
+	DistanceWeight float64
+	// ReferencePoints are the points seats are close to, e.g. the stage or both goals,
+	// seating plans without reference points prefer no distance
+	ReferencePoints []SeatReferencePoint
+	// CentralityWeight weights how far seats are from the center of their row
+	CentralityWeight float64
+	// AisleWeight weights how far seats are from the ends of their row, so that aisle seats are preferred
+	AisleWeight float64
+	// RowWeight weights RowPreferences
+	RowWeight float64
+	// RowPreferences are scores of rows by row id, e.g. negative scores for the rows which are preferred
+	RowPreferences map[string]float64
+	// BlockWeight weights BlockBiases
+	BlockWeight float64
+	// BlockBiases are scores of blocks by block id, e.g. negative scores for the center balcony
+	BlockBiases map[string]float64
+	// OffsetWeight weights the average horizontal distance between centers of segments of best seats split
+	// across adjacent rows, the distance is added to the average score of the seats
+	OffsetWeight float64
+}
+
+// SeatReferencePoint is a named reference point of a seating plan
+type SeatReferencePoint struct {
+	Name string `json:"name"`
+	X    int32  `json:"x"`
+	Y    int32  `json:"y"`
+	// Weight scales how far the point attracts seats, distances from the point are divided by it
+	Weight float64 `json:"weight"`
+}
+
+// DefaultSeatScoringConfig scores seats by their distance from the reference points and seating plans without
+// reference points by their distance from the center of their row, segments of seats split across rows are aligned
+func DefaultSeatScoringConfig() *SeatScoringConfig {
+	return &SeatScoringConfig{DistanceWeight: 1, CentralityWeight: 1, OffsetWeight: 1}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSeatCloneDestinationNotFound is returned when the destination seating plan doesn't exist in the org
+var ErrSeatCloneDestinationNotFound = errors.New("destination seating plan of seat clone not found")
+
+// SeatCloneRefs maps ids of seat groups and price categories of the source seating plan to ids of their
+// counterparts in the destination seating plan
+type SeatCloneRefs map[string]string
+
+// SeatCloneRefError is returned when a cloned seat references a seat group or a price category
+// which has no counterpart in the destination seating plan
+type SeatCloneRefError struct {
+	SeatID string
+	// Field is the seat field holding the reference, e.g. SeatFieldSeatRowID
+	Field string
+	RefID string
+}
+
+func (e *SeatCloneRefError) Error() string {
+	return fmt.Sprintf("%s %s of seat %s has no counterpart in the destination seating plan", e.Field, e.RefID, e.SeatID)
+}
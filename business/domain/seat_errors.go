@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSeatNotFound is returned when the seat doesn't exist
+	ErrSeatNotFound = errors.New("seat not found")
+	// ErrSeatConflict is returned when the seat can't be changed because of its current state
+	// or because another seat with the same id already exists
+	ErrSeatConflict = errors.New("seat conflict")
+	// ErrSeatDeleted is returned when the seat exists but is soft deleted
+	ErrSeatDeleted = errors.New("seat deleted")
+)
+
+// SeatError tells which seats caused one of the seat errors, check it with errors.Is(err, ErrSeatNotFound) etc.
+type SeatError struct {
+	OrigError error
+	SeatIDs   []string
+}
+
+func (e *SeatError) Error() string {
+	return fmt.Sprintf("%v: %s", e.OrigError, strings.Join(e.SeatIDs, ", "))
+}
+
+func (e *SeatError) Unwrap() error {
+	return e.OrigError
+}
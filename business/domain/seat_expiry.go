@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// DefaultSeatOfferExpiry is the time after which offered seats are released when no other expiry is configured
+const DefaultSeatOfferExpiry = 15 * time.Minute
+
+// ReleasedSeat is a seat which was made available again after its offer or hold expired
+type ReleasedSeat struct {
+	SeatID        string
+	OrgID         string
+	SeatingPlanID string
+}
+
+// SeatExpiryReport describes a single run of the seat expiry worker
+type SeatExpiryReport struct {
+	RunAt time.Time
+	// ReleasedPerSeatingPlan is the number of released seats by seating plan id
+	ReleasedPerSeatingPlan map[string]int
+	// WheelchairSeatsReleased is the number of wheelchair spaces released to general sale
+	WheelchairSeatsReleased int
+}
+
+// Released returns the total number of released seats
+func (r SeatExpiryReport) Released() int {
+	var total int
+	for _, count := range r.ReleasedPerSeatingPlan {
+		total += count
+	}
+	return total
+}
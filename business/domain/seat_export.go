@@ -0,0 +1,9 @@
+package domain
+
+// SeatExportFormat is a file format seats are exported to
+type SeatExportFormat string
+
+const (
+	SeatExportFormatCSV  SeatExportFormat = "csv"
+	SeatExportFormatJSON SeatExportFormat = "json"
+)
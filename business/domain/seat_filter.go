@@ -0,0 +1,93 @@
+package domain
+
+import "fmt"
+
+// SeatDeletedFilter tells how soft deleted seats are treated when querying seats
+type SeatDeletedFilter int
+
+const (
+	// SeatDeletedExclude leaves deleted seats out
+	SeatDeletedExclude SeatDeletedFilter = iota
+	// SeatDeletedInclude returns deleted seats together with not deleted ones
+	SeatDeletedInclude
+	// SeatDeletedOnly returns only deleted seats
+	SeatDeletedOnly
+)
+
+// SeatsQuery extends SeatsFilter with additional seat filters
+type SeatsQuery struct {
+	SeatsFilter
+	SeatAttributesFilter
+	// Sort orders seats by several keys, it takes precedence over OrderBy and Desc. OrderBy may list several keys
+	// separated by commas as well, e.g. "row,-num" orders seats by row and by number the other way round.
+	Sort []SeatSort
+}
+
+// SeatAttributesFilter filters seats by their attributes and deletion, it narrows both GetSeats and ListSeats
+type SeatAttributesFilter struct {
+	// StatusCodes returns seats having any of the statuses
+	StatusCodes []SeatStatus
+	// PriceCategoryID set to an empty string returns seats without price category
+	PriceCategoryID *string
+	SeatRowID       *string
+	SeatBlockID     *string
+	SeatGateID      *string
+	Wheelchair      *bool
+	OrderID         *string
+	// HasLinkedSeat returns seats with or without a linked seat
+	HasLinkedSeat *bool
+	// Deleted leaves soft deleted seats out by default
+	Deleted SeatDeletedFilter
+}
+
+// SeatSortKey is a key seats can be ordered by
+type SeatSortKey string
+
+const (
+	SeatSortName          SeatSortKey = "name"
+	SeatSortNum           SeatSortKey = "num"
+	SeatSortX             SeatSortKey = "x"
+	SeatSortY             SeatSortKey = "y"
+	SeatSortStatus        SeatSortKey = "status"
+	SeatSortPriceCategory SeatSortKey = "price_category"
+	// SeatSortRow keeps seats of the same row together
+	SeatSortRow SeatSortKey = "row"
+	// SeatSortBlock keeps seats of the same block together
+	SeatSortBlock     SeatSortKey = "block"
+	SeatSortCreatedAt SeatSortKey = "created_at"
+	// SeatSortUpdatedAt orders never updated seats by their creation time
+	SeatSortUpdatedAt SeatSortKey = "updated_at"
+)
+
+// SeatSort is a single sort key of a seats query
+type SeatSort struct {
+	Key  SeatSortKey
+	Desc bool
+}
+
+// SeatSortKeyError is returned when seats are ordered by an unknown key
+type SeatSortKeyError struct {
+	Key SeatSortKey
+}
+
+func (e *SeatSortKeyError) Error() string {
+	return fmt.Sprintf("unknown seat sort key %q", e.Key)
+}
+
+// SeatsPageRequest requests a page of seats
+type SeatsPageRequest struct {
+	Limit int64
+	// Cursor is the NextCursor of the previously returned page, the query must be the same for all pages
+	Cursor *string
+	// WithTotal requests the total number of seats matching the query
+	WithTotal bool
+}
+
+// SeatsPage is a page of seats
+type SeatsPage struct {
+	Seats []Seat
+	// NextCursor is nil when there are no more seats
+	NextCursor *string
+	// Total is set when requested
+	Total *int64
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrSeatHoldNotFound is returned when there is no active hold for the given token
+var ErrSeatHoldNotFound = errors.New("seat hold not found or expired")
+
+// SeatHold is a temporary reservation of seats identified by a hold token
+type SeatHold struct {
+	Token     string
+	OrgID     string
+	SeatIDs   []string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// SeatHoldConflictError is returned when requested seats are already held or are not available
+type SeatHoldConflictError struct {
+	OrigError error
+	SeatIDs   []string
+}
+
+func (e *SeatHoldConflictError) Error() string {
+	return fmt.Sprintf("%v: %s", e.OrigError, strings.Join(e.SeatIDs, ", "))
+}
+
+func (e *SeatHoldConflictError) Unwrap() error {
+	return e.OrigError
+}
+
+func (e *SeatHoldConflictError) Is(target error) bool {
+	return target == ErrSeatConflict
+}
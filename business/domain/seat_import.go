@@ -0,0 +1,31 @@
+package domain
+
+import "fmt"
+
+// SeatImportLineError is a validation error of a single line of an imported seats file
+type SeatImportLineError struct {
+	Line int
+	// Column is empty when the error concerns the whole line
+	Column  string
+	Message string
+}
+
+func (e SeatImportLineError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d, %s: %s", e.Line, e.Column, e.Message)
+}
+
+// SeatImportResult is the result of a seats import, seats are created only when there are no errors
+// and the import is not a dry run
+type SeatImportResult struct {
+	DryRun bool
+	// Lines is the number of imported seat lines
+	Lines int
+	// SeatIDs are ids of created seats in the order of lines
+	SeatIDs []string
+	Errors  []SeatImportLineError
+	// Unrecognized are parts of the file which were skipped, they don't prevent the import
+	Unrecognized []SeatImportLineError
+}
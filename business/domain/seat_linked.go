@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LinkedSeatGroup are seats connected by LinkedSeatID, e.g. a wheelchair seat with its companion seat or box seats,
+// they're held, booked and released together
+type LinkedSeatGroup struct {
+	SeatIDs []string
+}
+
+// SeatAvailableForOrder tells whether the seat can be ordered. Seats linked to another seat stay locked until
+// their group is ordered, so they're available as a part of the group.
+func SeatAvailableForOrder(seat Seat) bool {
+	return seat.StatusCode == SeatStatusAvailable || (seat.StatusCode == SeatStatusLocked && seat.LinkedSeatID != "")
+}
+
+// SeatRuleLinkedSeatsError is returned when only some seats of a linked seat group are requested
+type SeatRuleLinkedSeatsError struct {
+	OrigError error
+	// MissingSeatIDs are seats of the group which weren't requested
+	MissingSeatIDs []string
+}
+
+func (e *SeatRuleLinkedSeatsError) Error() string {
+	return fmt.Sprintf("%v: %s", e.OrigError, strings.Join(e.MissingSeatIDs, ", "))
+}
+
+func (e *SeatRuleLinkedSeatsError) Unwrap() error {
+	return e.OrigError
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Seat fields tracked in seat log changes
+const (
+	SeatFieldNum             = "num"
+	SeatFieldName            = "name"
+	SeatFieldX               = "x"
+	SeatFieldY               = "y"
+	SeatFieldSvgPath         = "svg_path"
+	SeatFieldSvgTransform    = "svg_transform"
+	SeatFieldPriceCategoryID = "price_category_id"
+	SeatFieldStatusCode      = "status_code"
+	SeatFieldOrderID         = "order_id"
+	SeatFieldOrderItemID     = "order_item_id"
+	SeatFieldSplitOrderID    = "split_order_id"
+	SeatFieldWheelchair      = "wheelchair"
+	SeatFieldOverrideNum     = "override_num"
+	SeatFieldLinkedSeatID    = "linked_seat_id"
+	SeatFieldDescription     = "description"
+	SeatFieldBestSeatGroupID = "best_seat_group_id"
+	SeatFieldSeatRowID       = "seat_row_id"
+	SeatFieldSeatBlockID     = "seat_block_id"
+	SeatFieldSeatGateID      = "seat_gate_id"
+	SeatFieldOfferedAt       = "offered_at"
+	SeatFieldDeletedAt       = "deleted_at"
+)
+
+// SeatFieldChange holds the old and the new value of a changed seat field
+type SeatFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// SeatChanges are changed seat fields by field name
+type SeatChanges map[string]SeatFieldChange
+
+// Add records the change of the field if the value has changed
+func (c SeatChanges) Add(field string, oldValue, newValue interface{}) {
+	if oldValue == newValue {
+		return
+	}
+	c[field] = SeatFieldChange{Old: oldValue, New: newValue}
+}
+
+// ErrSeatLogsOrgRequired is returned when seat logs are requested without org
+var ErrSeatLogsOrgRequired = errors.New("seat logs filter requires org id")
+
+// SeatLogsFilter filters seat logs, logs are always scoped to a single org
+type SeatLogsFilter struct {
+	OrgID         string
+	SeatID        *string
+	SeatingPlanID *string
+	OrderID       *string
+	UserID        *string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	// Cursor is the NextCursor of the previously returned page
+	Cursor *string
+}
+
+// SeatLogEntry is a seat log with its author, time and changed fields
+type SeatLogEntry struct {
+	SeatLog
+	UserID    string
+	Changes   SeatChanges
+	CreatedAt time.Time
+}
+
+// SeatLogsPage is a page of seat logs ordered by creation time
+type SeatLogsPage struct {
+	Logs []*SeatLogEntry
+	// NextCursor is nil when there are no more logs
+	NextCursor *string
+}
@@ -0,0 +1,20 @@
+package domain
+
+// SeatMapColorBy tells what seats are colored by on a rendered seat map
+type SeatMapColorBy string
+
+const (
+	SeatMapColorByStatus        SeatMapColorBy = "status"
+	SeatMapColorByPriceCategory SeatMapColorBy = "price_category"
+)
+
+// SeatMapRenderOptions are options of a rendered seat map, zero values are replaced by defaults
+type SeatMapRenderOptions struct {
+	ColorBy SeatMapColorBy
+	// StatusColors overrides default colors of seat statuses
+	StatusColors map[SeatStatus]string
+	// PriceCategoryColors are colors by price category id, categories without a color get one from the default palette
+	PriceCategoryColors map[string]string
+	// SeatRadius is the radius of seats without SVG path
+	SeatRadius int32
+}
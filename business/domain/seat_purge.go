@@ -0,0 +1,9 @@
+package domain
+
+// SeatPurgeResult is the result of hard deleting soft deleted seats
+type SeatPurgeResult struct {
+	PurgedSeatIDs []string
+	// RefusedSeatIDs are the seats which were not purged because they are referenced by orders
+	// or linked from offered or sold seats
+	RefusedSeatIDs []string
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// SeatState is the state of a seat at some point in time reconstructed from seat logs
+type SeatState struct {
+	SeatID          string
+	SeatingPlanID   string
+	Num             int32
+	Name            string
+	X               int32
+	Y               int32
+	SvgPath         string
+	SvgTransform    string
+	PriceCategoryID *string
+	StatusCode      SeatStatus
+	OrderID         string
+	OrderItemID     string
+	SplitOrderID    string
+	Wheelchair      bool
+	OverrideNum     *string
+	LinkedSeatID    string
+	Description     string
+	BestSeatGroupID *string
+	SeatRowID       *string
+	SeatBlockID     *string
+	SeatGateID      *string
+	OfferedAt       *time.Time
+	DeletedAt       *time.Time
+	// ChangedAt is the time of the last log applied to the state
+	ChangedAt time.Time
+}
+
+// Apply sets the new values of the changed fields, values are expected as decoded from JSON
+func (s *SeatState) Apply(changes SeatChanges) error {
+	var err error
+	for field, change := range changes {
+		v := change.New
+		switch field {
+		case SeatFieldNum:
+			s.Num, err = jsonInt32(v)
+		case SeatFieldName:
+			s.Name, err = jsonString(v)
+		case SeatFieldX:
+			s.X, err = jsonInt32(v)
+		case SeatFieldY:
+			s.Y, err = jsonInt32(v)
+		case SeatFieldSvgPath:
+			s.SvgPath, err = jsonString(v)
+		case SeatFieldSvgTransform:
+			s.SvgTransform, err = jsonString(v)
+		case SeatFieldPriceCategoryID:
+			s.PriceCategoryID, err = jsonStringP(v)
+		case SeatFieldStatusCode:
+			var statusCode int32
+			statusCode, err = jsonInt32(v)
+			s.StatusCode = SeatStatus(statusCode)
+		case SeatFieldOrderID:
+			s.OrderID, err = jsonString(v)
+		case SeatFieldOrderItemID:
+			s.OrderItemID, err = jsonString(v)
+		case SeatFieldSplitOrderID:
+			s.SplitOrderID, err = jsonString(v)
+		case SeatFieldWheelchair:
+			s.Wheelchair, err = jsonBool(v)
+		case SeatFieldOverrideNum:
+			s.OverrideNum, err = jsonStringP(v)
+		case SeatFieldLinkedSeatID:
+			s.LinkedSeatID, err = jsonString(v)
+		case SeatFieldDescription:
+			s.Description, err = jsonString(v)
+		case SeatFieldBestSeatGroupID:
+			s.BestSeatGroupID, err = jsonStringP(v)
+		case SeatFieldSeatRowID:
+			s.SeatRowID, err = jsonStringP(v)
+		case SeatFieldSeatBlockID:
+			s.SeatBlockID, err = jsonStringP(v)
+		case SeatFieldSeatGateID:
+			s.SeatGateID, err = jsonStringP(v)
+		case SeatFieldOfferedAt:
+			s.OfferedAt, err = jsonTimeP(v)
+		case SeatFieldDeletedAt:
+			s.DeletedAt, err = jsonTimeP(v)
+		}
+		if err != nil {
+			return fmt.Errorf("seat field %v: %w", field, err)
+		}
+	}
+	return nil
+}
+
+func jsonInt32(v interface{}) (int32, error) {
+	switch n := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int32(n), nil
+	case int32:
+		return n, nil
+	}
+	return 0, fmt.Errorf("unexpected number value %v", v)
+}
+
+func jsonString(v interface{}) (string, error) {
+	switch s := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return s, nil
+	}
+	return "", fmt.Errorf("unexpected string value %v", v)
+}
+
+func jsonStringP(v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	s, err := jsonString(v)
+	return &s, err
+}
+
+func jsonBool(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	}
+	return false, fmt.Errorf("unexpected bool value %v", v)
+}
+
+func jsonTimeP(v interface{}) (*time.Time, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return nil, err
+		}
+		return &parsed, nil
+	}
+	return nil, fmt.Errorf("unexpected time value %v", v)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "fmt"
+
+// SeatStatusTransition is a change of seat status
+type SeatStatusTransition struct {
+	From SeatStatus
+	To   SeatStatus
+}
+
+// seatStatusTransitionRule restricts when a declared seat status transition is allowed
+type seatStatusTransitionRule struct {
+	// refundOnly allows the transition only when the order of the seat is refunded
+	refundOnly bool
+	// linkedOnly allows the transition only to seats linked to another seat, which are locked instead of
+	// becoming available and are held or sold together with their group
+	linkedOnly bool
+}
+
+// seatStatusTransitions declares allowed seat status changes
+var seatStatusTransitions = map[SeatStatusTransition]seatStatusTransitionRule{
+	{From: SeatStatusAvailable, To: SeatStatusOffered}: {},
+	{From: SeatStatusAvailable, To: SeatStatusSold}:    {},
+	{From: SeatStatusAvailable, To: SeatStatusLocked}:  {},
+	{From: SeatStatusOffered, To: SeatStatusAvailable}: {},
+	{From: SeatStatusOffered, To: SeatStatusSold}:      {},
+	{From: SeatStatusOffered, To: SeatStatusLocked}:    {linkedOnly: true},
+	{From: SeatStatusLocked, To: SeatStatusAvailable}:  {},
+	{From: SeatStatusLocked, To: SeatStatusOffered}:    {linkedOnly: true},
+	{From: SeatStatusLocked, To: SeatStatusSold}:       {linkedOnly: true},
+	{From: SeatStatusSold, To: SeatStatusAvailable}:    {refundOnly: true},
+	{From: SeatStatusSold, To: SeatStatusLocked}:       {refundOnly: true, linkedOnly: true},
+}
+
+// ValidateSeatStatusTransition checks that the seat may change its status from one to another,
+// refund tells whether the change happens because the order of the seat is refunded and linked tells whether
+// the seat is linked to another seat
+func ValidateSeatStatusTransition(seatID string, from, to SeatStatus, refund, linked bool) error {
+	if from == to {
+		return nil
+	}
+	rule, ok := seatStatusTransitions[SeatStatusTransition{From: from, To: to}]
+	if !ok || (rule.refundOnly && !refund) || (rule.linkedOnly && !linked) {
+		return &SeatStatusTransitionError{SeatID: seatID, From: from, To: to}
+	}
+	return nil
+}
+
+// SeatStatusTransitionError is returned when a seat status change is not allowed
+type SeatStatusTransitionError struct {
+	SeatID string
+	From   SeatStatus
+	To     SeatStatus
+}
+
+func (e *SeatStatusTransitionError) Error() string {
+	return fmt.Sprintf("seat %s can't change status from %d to %d", e.SeatID, e.From, e.To)
+}
+
+func (e *SeatStatusTransitionError) Is(target error) bool {
+	return target == ErrSeatConflict
+}
+
+// SeatsStatusChange is the result of a seat status update
+type SeatsStatusChange struct {
+	SeatIDs []string
+	// PreviousStatuses are the statuses of the seats before the update by seat id
+	PreviousStatuses map[string]SeatStatus
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VersionedUpdateSeat is a seat update guarded by the seat modification time the caller has seen
+type VersionedUpdateSeat struct {
+	*UpdateSeat
+	// ExpectedUpdatedAt is the UpdatedAt value of the seat when it was read by the caller,
+	// nil means the seat has not been updated since creation
+	ExpectedUpdatedAt *time.Time
+}
+
+// SeatConcurrentModificationError is returned when seats of a batch were modified by someone else
+type SeatConcurrentModificationError struct {
+	OrigError error
+	SeatIDs   []string
+}
+
+func (e *SeatConcurrentModificationError) Error() string {
+	return fmt.Sprintf("%v: %s", e.OrigError, strings.Join(e.SeatIDs, ", "))
+}
+
+func (e *SeatConcurrentModificationError) Unwrap() error {
+	return e.OrigError
+}
+
+func (e *SeatConcurrentModificationError) Is(target error) bool {
+	return target == ErrSeatConflict
+}
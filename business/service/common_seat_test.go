@@ -0,0 +1,44 @@
+package service
+
+import (
+	"fmt"
+	"github.com/proj/business/domain"
+	"time"
+)
+
+// SetupOrg creates the user with the org, a tax group, a price name and a location of the suite
+func (c *CommonSuite) SetupOrg() {
+	c.InitCommon()
+	newUserInput := domain.NewUser{
+		Name:    c.faker.Person().Name(),
+		OrgName: fmt.Sprintf("test-org-%d", time.Now().Unix()),
+		Email:   fmt.Sprintf("test-%d@entrello.io", time.Now().Unix()),
+	}
+	user, err := c.service.CreateUserAndOrg(c.ctx, newUserInput)
+	c.NoError(err)
+	c.user = user
+	tg, err := c.service.CreateTaxGroup(c.ctx, &domain.IDs{OrgID: *user.OrgID, UserID: user.ID}, &domain.NewTaxGroup{Name: "tg1", TaxRate: float64(13)})
+	c.NoError(err)
+	c.taxGroup = tg
+	pn, err := c.service.CreatePriceName(c.ctx, &domain.IDs{OrgID: *user.OrgID, UserID: user.ID}, &domain.NewPriceName{Name: "pn1", TaxGroupID: tg.ID})
+	c.NoError(err)
+	c.priceName = pn
+	loc, err := c.service.CreateLocation(c.ctx, &domain.IDs{OrgID: *user.OrgID, UserID: user.ID}, &domain.NewLocation{Name: "loc1"})
+	c.NoError(err)
+	c.location = loc
+	org, err := c.service.GetOrgByID(c.ctx, *user.OrgID)
+	c.NoError(err)
+	c.org = org
+}
+
+// SetupOrgWithSeats runs SetupOrg and creates a seating plan with seatsCount seats in rows of seatsPerRow seats
+func (c *CommonSuite) SetupOrgWithSeats(seatsCount, seatsPerRow int) []domain.Seat {
+	c.SetupOrg()
+	_, _, seats := c.CreateSeatingPlanEventWithSeatGroups(seatsCount, seatsPerRow)
+	return seats
+}
+
+// ids returns ids of the user, the org and the seating plan of the suite
+func (c *CommonSuite) ids() *domain.IDs {
+	return &domain.IDs{OrgID: c.org.ID, UserID: c.user.ID, SplID: c.spl.ID}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/proj/business/domain"
+	"github.com/proj/business/storage"
+)
+
+// GetSeatAccessibilityRules returns accessibility rules of the org
+func (s *Service) GetSeatAccessibilityRules(ctx context.Context, orgID string) (*domain.SeatAccessibilityRules, error) {
+	rules, err := s.storage.GetSeatAccessibilityRules(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting seat accessibility rules %w", err)
+	}
+	return rules, nil
+}
+
+// SetSeatAccessibilityRules sets accessibility rules of the org ids.OrgID
+func (s *Service) SetSeatAccessibilityRules(ctx context.Context, ids *domain.IDs, rules *domain.SeatAccessibilityRules) error {
+	if rules.ReleaseBeforeEvent < 0 {
+		return fmt.Errorf("invalid wheelchair space release time %v", rules.ReleaseBeforeEvent)
+	}
+	if err := s.storage.SetSeatAccessibilityRules(ctx, ids, rules, time.Now()); err != nil {
+		return fmt.Errorf("error while setting seat accessibility rules %w", err)
+	}
+	return nil
+}
+
+// checkSeatAccessibilityRules checks requested wheelchair spaces against the accessibility rules of the org,
+// wheelchair spaces requested without tickets are refused when the org restricts their ticket types. Wheelchair
+// spaces released to general sale are regular seats.
+func checkSeatAccessibilityRules(ctx context.Context, s *storage.Storage, ids *domain.IDs, tickets *domain.SeatTickets, requestedSeatsGroupedByRowsGroupedBySpl map[string]map[string][]domain.Seat) error {
+	rules, err := s.GetSeatAccessibilityRules(ctx, ids.OrgID)
+	if err != nil {
+		return fmt.Errorf("error while querying seat accessibility rules %w", err)
+	}
+	if len(rules.AccessibilityPriceNameIDs) == 0 && !rules.RequireCompanion {
+		return nil
+	}
+
+	var seatIDs []string
+	for _, rows := range requestedSeatsGroupedByRowsGroupedBySpl {
+		for _, rowSeats := range rows {
+			for _, seat := range rowSeats {
+				seatIDs = append(seatIDs, seat.ID)
+			}
+		}
+	}
+	// requested seats may carry only some of the seat fields
+	seats, err := s.ListSeats(ctx, &domain.SeatsQuery{SeatsFilter: domain.SeatsFilter{OrgID: &ids.OrgID, IDs: seatIDs}}, int64(len(seatIDs)), 0)
+	if err != nil {
+		return fmt.Errorf("error while querying seats %w", err)
+	}
+	var wheelchairSeatIDs []string
+	for _, seat := range seats {
+		if seat.Wheelchair {
+			wheelchairSeatIDs = append(wheelchairSeatIDs, seat.ID)
+		}
+	}
+	if len(wheelchairSeatIDs) == 0 {
+		return nil
+	}
+	releasedSeatIDs, err := s.GetReleasedWheelchairSeatIDs(ctx, ids, wheelchairSeatIDs, time.Now())
+	if err != nil {
+		return fmt.Errorf("error while querying released wheelchair seats %w", err)
+	}
+	released := make(map[string]bool, len(releasedSeatIDs))
+	for _, seatID := range releasedSeatIDs {
+		released[seatID] = true
+	}
+	var wheelchairSeats []domain.Seat
+	for _, seat := range seats {
+		if seat.Wheelchair && !released[seat.ID] {
+			wheelchairSeats = append(wheelchairSeats, seat)
+		}
+	}
+	if len(wheelchairSeats) == 0 {
+		return nil
+	}
+
+	if len(rules.AccessibilityPriceNameIDs) > 0 {
+		if tickets == nil {
+			return &domain.SeatRuleAccessibilityError{
+				OrigError: errors.New("wheelchair space requires an accessibility ticket, no tickets were given"),
+				SeatID:    wheelchairSeats[0].ID,
+			}
+		}
+		accessibilityPriceNameIDs := make(map[string]bool, len(rules.AccessibilityPriceNameIDs))
+		for _, pnID := range rules.AccessibilityPriceNameIDs {
+			accessibilityPriceNameIDs[pnID] = true
+		}
+		for _, seat := range wheelchairSeats {
+			if !accessibilityPriceNameIDs[tickets.PriceNameIDs[seat.ID]] {
+				return &domain.SeatRuleAccessibilityError{
+					OrigError: errors.New("wheelchair space requires an accessibility ticket"),
+					SeatID:    seat.ID,
+				}
+			}
+		}
+	}
+	if rules.RequireCompanion {
+		wheelchairSeatIDs := make([]string, len(wheelchairSeats))
+		for i, seat := range wheelchairSeats {
+			wheelchairSeatIDs[i] = seat.ID
+		}
+		linkedSeatGroups, err := s.GetLinkedSeatGroups(ctx, ids, wheelchairSeatIDs)
+		if err != nil {
+			return fmt.Errorf("error while querying linked seats %w", err)
+		}
+		withCompanion := make(map[string]bool)
+		for _, group := range linkedSeatGroups {
+			for _, seatID := range group.SeatIDs {
+				withCompanion[seatID] = true
+			}
+		}
+		// companions have to be requested as well, which is checked with the other linked seats
+		for _, seatID := range wheelchairSeatIDs {
+			if !withCompanion[seatID] {
+				return &domain.SeatRuleAccessibilityError{
+					OrigError: errors.New("wheelchair space has no companion seat"),
+					SeatID:    seatID,
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatAccessibilityTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatAccessibilityTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+
+	// wheelchair space 0 with companion seat 1 and wheelchair space 6 without companion
+	wheelchair := true
+	companionSeatID := suite.seats[1].ID
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{
+		{ID: suite.seats[0].ID, Wheelchair: &wheelchair, LinkedSeatID: &companionSeatID},
+		{ID: suite.seats[6].ID, Wheelchair: &wheelchair},
+	}, time.Now())
+	suite.NoError(err)
+	err = suite.service.SetSeatAccessibilityRules(suite.ctx, suite.ids(), &domain.SeatAccessibilityRules{
+		AccessibilityPriceNameIDs: []string{suite.priceName.ID},
+		RequireCompanion:          true,
+		ReleaseBeforeEvent:        2 * time.Hour,
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatAccessibilityTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatAccessibilityTestSuite) requested(seats ...domain.Seat) map[string]map[string][]domain.Seat {
+	return map[string]map[string][]domain.Seat{suite.spl.ID: {*seats[0].SeatRowID: seats}}
+}
+
+func (suite *SeatAccessibilityTestSuite) TestGetSeatAccessibilityRules() {
+	rules, err := suite.service.GetSeatAccessibilityRules(suite.ctx, suite.org.ID)
+	suite.NoError(err)
+	suite.Equal([]string{suite.priceName.ID}, rules.AccessibilityPriceNameIDs)
+	suite.True(rules.RequireCompanion)
+	suite.Equal(2*time.Hour, rules.ReleaseBeforeEvent)
+}
+
+func (suite *SeatAccessibilityTestSuite) TestWheelchairSpaceRequiresAccessibilityTicket() {
+	requested := suite.requested(suite.seats[0], suite.seats[1])
+	err := suite.service.ValidateSeatRulesWithTickets(suite.ctx, suite.org.ID, map[string]string{}, requested, &domain.SeatTickets{
+		PriceNameIDs: map[string]string{suite.seats[0].ID: "regular", suite.seats[1].ID: "regular"},
+	})
+	var accessibilityErr *domain.SeatRuleAccessibilityError
+	suite.ErrorAs(err, &accessibilityErr)
+	suite.Equal(suite.seats[0].ID, accessibilityErr.SeatID)
+
+	err = suite.service.ValidateSeatRulesWithTickets(suite.ctx, suite.org.ID, map[string]string{}, requested, &domain.SeatTickets{
+		PriceNameIDs: map[string]string{suite.seats[0].ID: suite.priceName.ID, suite.seats[1].ID: "regular"},
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatAccessibilityTestSuite) TestWheelchairSpaceRequiresCompanion() {
+	requested := suite.requested(suite.seats[6])
+	err := suite.service.ValidateSeatRulesWithTickets(suite.ctx, suite.org.ID, map[string]string{}, requested, &domain.SeatTickets{
+		PriceNameIDs: map[string]string{suite.seats[6].ID: suite.priceName.ID},
+	})
+	var accessibilityErr *domain.SeatRuleAccessibilityError
+	suite.ErrorAs(err, &accessibilityErr)
+	suite.Equal(suite.seats[6].ID, accessibilityErr.SeatID)
+}
+
+func (suite *SeatAccessibilityTestSuite) TestWheelchairSpaceWithoutTickets() {
+	// the ticket type can't be checked without tickets
+	err := suite.service.ValidateSeatRules(suite.ctx, suite.org.ID, map[string]string{}, suite.requested(suite.seats[0], suite.seats[1]))
+	var accessibilityErr *domain.SeatRuleAccessibilityError
+	suite.ErrorAs(err, &accessibilityErr)
+	suite.Equal(suite.seats[0].ID, accessibilityErr.SeatID)
+	err = suite.service.ValidateSeatRulesWithTickets(suite.ctx, suite.org.ID, map[string]string{}, suite.requested(suite.seats[0], suite.seats[1]), nil)
+	suite.ErrorAs(err, &accessibilityErr)
+	// regular seats don't need tickets
+	err = suite.service.ValidateSeatRules(suite.ctx, suite.org.ID, map[string]string{}, suite.requested(suite.seats[2], suite.seats[3]))
+	suite.NoError(err)
+}
+
+func TestSeatAccessibilityTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatAccessibilityTestSuite))
+}
+
+// SeatWheelchairReleaseTestSuite releases wheelchair spaces of its own org, so that the spaces of
+// SeatAccessibilityTestSuite stay wheelchair spaces
+type SeatWheelchairReleaseTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatWheelchairReleaseTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+
+	// wheelchair space 0 locked by its companion seat 1, wheelchair spaces 6, 7 and 8
+	wheelchair := true
+	companionSeatID := suite.seats[1].ID
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{
+		{ID: suite.seats[0].ID, Wheelchair: &wheelchair, LinkedSeatID: &companionSeatID},
+		{ID: suite.seats[6].ID, Wheelchair: &wheelchair},
+		{ID: suite.seats[7].ID, Wheelchair: &wheelchair},
+		{ID: suite.seats[8].ID, Wheelchair: &wheelchair},
+	}, time.Now())
+	suite.NoError(err)
+	// the release time covers the event of the seating plan from a clock set 100 years back
+	err = suite.service.SetSeatAccessibilityRules(suite.ctx, suite.ids(), &domain.SeatAccessibilityRules{
+		AccessibilityPriceNameIDs: []string{suite.priceName.ID},
+		RequireCompanion:          true,
+		ReleaseBeforeEvent:        200 * 365 * 24 * time.Hour,
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatWheelchairReleaseTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatWheelchairReleaseTestSuite) releasedSeatIDs() []string {
+	seatIDs := []string{suite.seats[0].ID, suite.seats[6].ID, suite.seats[7].ID, suite.seats[8].ID}
+	releasedSeatIDs, err := suite.service.storage.GetReleasedWheelchairSeatIDs(suite.ctx, suite.ids(), seatIDs, time.Now())
+	suite.NoError(err)
+	return releasedSeatIDs
+}
+
+func (suite *SeatWheelchairReleaseTestSuite) TestWheelchairSpaceReleasedBeforeEvent() {
+	// the event of the seating plan is in the past of a clock set 100 years ahead
+	worker := suite.service.NewSeatExpiryWorker(SeatExpiryConfig{}, func() time.Time { return time.Now().AddDate(100, 0, 0) })
+	_, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Empty(suite.releasedSeatIDs())
+
+	// sold and held spaces aren't released
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.seats[7].ID},
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	_, err = suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{suite.seats[8].ID}, time.Hour, time.Now())
+	suite.NoError(err)
+
+	worker = suite.service.NewSeatExpiryWorker(SeatExpiryConfig{}, func() time.Time { return time.Now().AddDate(-100, 0, 0) })
+	report, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.GreaterOrEqual(report.WheelchairSeatsReleased, 2)
+	suite.Equal([]string{suite.seats[0].ID, suite.seats[6].ID}, suite.releasedSeatIDs())
+	// released spaces keep their wheelchair flag
+	for _, seat := range []domain.Seat{suite.seats[0], suite.seats[6]} {
+		stored, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seat.ID, OrgID: suite.org.ID})
+		suite.NoError(err)
+		suite.True(stored.Wheelchair)
+	}
+	// released spaces are regular seats
+	requested := map[string]map[string][]domain.Seat{suite.spl.ID: {*suite.seats[6].SeatRowID: {suite.seats[6]}}}
+	err = suite.service.ValidateSeatRulesWithTickets(suite.ctx, suite.org.ID, map[string]string{}, requested, &domain.SeatTickets{
+		PriceNameIDs: map[string]string{suite.seats[6].ID: "regular"},
+	})
+	suite.NoError(err)
+
+	// spaces are released once for the event
+	_, err = worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal([]string{suite.seats[0].ID, suite.seats[6].ID}, suite.releasedSeatIDs())
+}
+
+func TestSeatWheelchairReleaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatWheelchairReleaseTestSuite))
+}
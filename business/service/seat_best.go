@@ -0,0 +1,595 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/proj/business/domain"
+)
+
+// bestSeatsRow are seats of a row available for order ordered by seat number
+type bestSeatsRow struct {
+	rowID   string
+	blockID string
+	// y is the average vertical position of all row seats, it orders rows of a block
+	y float64
+	// minX and maxX are horizontal positions of the first and the last seats of the row including unavailable ones
+	minX  float64
+	maxX  float64
+	seats []domain.Seat
+}
+
+// bestSeatsSegmentKey identifies segments of the same size of a row
+type bestSeatsSegmentKey struct {
+	rowID string
+	size  int
+}
+
+// bestSeatsCandidate is a selection of seats proposed as best available, lower score is better
+type bestSeatsCandidate struct {
+	seats []domain.Seat
+	score float64
+	// centerX is the average horizontal position of the seats
+	centerX float64
+}
+
+// FindBestSeats returns n adjacent available seats of the price category in one row of the seating plan ids.SplID
+// which score best with the seat scoring config of the seating plan, including its reference points. Empty
+// priceCategoryID allows any price category. Wheelchair spaces are never proposed, selections which would fragment
+// the row or split linked seats are skipped, so that the seats pass ValidateSeatRules. The seating plan isn't
+// loaded at once, seats are pulled in the order of their distance from the reference points and their blocks are
+// loaded until no block which isn't loaded could score better.
+func (s *Service) FindBestSeats(ctx context.Context, ids *domain.IDs, priceCategoryID string, n int) ([]domain.BestSeat, error) {
+	return s.findBestSeats(ctx, ids, priceCategoryID, n, 0)
+}
+
+// findBestSeats pulls seats in batches of batchSize seats, zero uses the default batch size of the storage
+func (s *Service) findBestSeats(ctx context.Context, ids *domain.IDs, priceCategoryID string, n int, batchSize int32) ([]domain.BestSeat, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of best seats %d", n)
+	}
+	config, err := s.storage.GetSeatScoringConfig(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error while querying seat scoring config %w", err)
+	}
+	candidates := s.storage.IterateBestSeatsByPosition(ids, priceCategoryID, config.ReferencePoints, batchSize)
+	seats, err := findBestRowBlockByCandidates(ctx, candidates, s.newBestSeatsBlocks(ids), priceCategoryID, n, config)
+	if err != nil {
+		return nil, err
+	}
+	return toBestSeats(seats), nil
+}
+
+// bestSeatsCandidates are available seats ordered by their distance from the reference points,
+// e.g. storage.BestSeatsIterator
+type bestSeatsCandidates interface {
+	Next(ctx context.Context) bool
+	Seat() domain.Seat
+	// Distance is the distance of the seat from the closest reference point divided by the weight of the point
+	Distance() float64
+	Err() error
+}
+
+// FindBestSeatsAcrossRows returns n best available seats like FindBestSeats, but when no row has n adjacent
+// available seats, it splits them into segments of adjacent seats in up to maxRows vertically adjacent rows
+// of the same block (e.g. 15+15, 20+10 or 3x10 for 30 seats). Fewer rows are always preferred, shapes of the same
+// number of rows are scored by the scores of their seats and by horizontal offsets between the segments weighted by
+// the OffsetWeight of the seat scoring config. Seats are ordered by rows from the front.
+func (s *Service) FindBestSeatsAcrossRows(ctx context.Context, ids *domain.IDs, priceCategoryID string, n, maxRows int) ([]domain.BestSeat, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of best seats %d", n)
+	}
+	if maxRows < 1 {
+		return nil, fmt.Errorf("invalid number of best seats rows %d", maxRows)
+	}
+	config, err := s.storage.GetSeatScoringConfig(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error while querying seat scoring config %w", err)
+	}
+	candidates := s.storage.IterateBestSeatsByPosition(ids, priceCategoryID, config.ReferencePoints, 0)
+	blocks := s.newBestSeatsBlocks(ids)
+	seats, err := findBestRowBlockByCandidates(ctx, candidates, blocks, priceCategoryID, n, config)
+	if err == nil {
+		return toBestSeats(seats), nil
+	}
+	if !errors.Is(err, domain.ErrNoBestSeats) {
+		return nil, err
+	}
+	// no row has n adjacent seats, so the candidates loaded all blocks with eligible seats
+	scorer := NewSeatScorer(config)
+	for rowsCount := 2; rowsCount <= maxRows && rowsCount <= n; rowsCount++ {
+		if seats, ok := findBestMultiRowBlock(blocks.rows, blocks.linkedSeats, priceCategoryID, n, rowsCount, scorer, config.OffsetWeight); ok {
+			return toBestSeats(seats), nil
+		}
+	}
+	return nil, domain.ErrNoBestSeats
+}
+
+// bestSeatsBlocks are rows of the blocks of a seating plan loaded so far with ids of seats linked with their seats
+type bestSeatsBlocks struct {
+	// load returns rows of the block with ids of seats linked with their seats,
+	// empty blockID returns rows of seats without block
+	load        func(ctx context.Context, blockID string) ([]bestSeatsRow, map[string][]string, error)
+	loaded      map[string]bool
+	rows        []bestSeatsRow
+	linkedSeats map[string][]string
+}
+
+func (s *Service) newBestSeatsBlocks(ids *domain.IDs) *bestSeatsBlocks {
+	return &bestSeatsBlocks{load: func(ctx context.Context, blockID string) ([]bestSeatsRow, map[string][]string, error) {
+		rows, err := s.getBestSeatsRows(ctx, ids, blockID)
+		if err != nil {
+			return nil, nil, err
+		}
+		linkedSeats, err := s.getLinkedBestSeats(ctx, ids, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		return rows, linkedSeats, nil
+	}}
+}
+
+// loadBlock loads the block unless it's loaded already, it returns rows of the block and ids of seats linked with
+// their seats when the block is loaded now
+func (b *bestSeatsBlocks) loadBlock(ctx context.Context, blockID string) ([]bestSeatsRow, map[string][]string, bool, error) {
+	if b.loaded[blockID] {
+		return nil, nil, false, nil
+	}
+	rows, linkedSeats, err := b.load(ctx, blockID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if b.loaded == nil {
+		b.loaded = make(map[string]bool)
+		b.linkedSeats = make(map[string][]string)
+	}
+	b.loaded[blockID] = true
+	b.rows = append(b.rows, rows...)
+	for seatID, linkedSeatIDs := range linkedSeats {
+		b.linkedSeats[seatID] = linkedSeatIDs
+	}
+	return rows, linkedSeats, true, nil
+}
+
+// findBestRowBlockByCandidates pulls the candidates and loads the blocks of their seats, it returns the best block of
+// n adjacent seats once seats of the blocks which aren't loaded can't score better, because they're further from
+// the reference points than the pulled seats. Blocks which keep no candidate eligible aren't loaded.
+func findBestRowBlockByCandidates(ctx context.Context, candidates bestSeatsCandidates, blocks *bestSeatsBlocks, priceCategoryID string, n int, config *domain.SeatScoringConfig) ([]domain.Seat, error) {
+	scorer := NewSeatScorer(config)
+	var best *bestSeatsCandidate
+	for candidates.Next(ctx) {
+		seat := candidates.Seat()
+		if !bestSeatsEligible(seat, priceCategoryID) {
+			continue
+		}
+		if best != nil && best.score <= seatScoreLowerBound(config, candidates.Distance()) {
+			return best.seats, nil
+		}
+		var blockID string
+		if seat.SeatBlockID != nil {
+			blockID = *seat.SeatBlockID
+		}
+		rows, linkedSeats, ok, err := blocks.loadBlock(ctx, blockID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if candidate, ok := bestRowBlock(rows, linkedSeats, priceCategoryID, n, scorer); ok && (best == nil || candidate.score < best.score) {
+			best = &candidate
+		}
+	}
+	if err := candidates.Err(); err != nil {
+		return nil, fmt.Errorf("error while iterating best seats %w", err)
+	}
+	if best == nil {
+		return nil, domain.ErrNoBestSeats
+	}
+	return best.seats, nil
+}
+
+// getLinkedBestSeats returns ids of seats linked with each seat of the rows, seats may be linked with seats
+// of other rows or with unavailable seats, which makes them impossible to propose
+func (s *Service) getLinkedBestSeats(ctx context.Context, ids *domain.IDs, rows []bestSeatsRow) (map[string][]string, error) {
+	var seatIDs []string
+	for _, row := range rows {
+		for _, seat := range row.seats {
+			seatIDs = append(seatIDs, seat.ID)
+		}
+	}
+	if len(seatIDs) == 0 {
+		return nil, nil
+	}
+	groups, err := s.storage.GetLinkedSeatGroups(ctx, ids, seatIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error while querying linked seats %w", err)
+	}
+	return linkedSeatsByGroups(groups), nil
+}
+
+// getBestSeatsRows returns seats of the block of the seating plan available for order grouped by rows, empty
+// blockID returns rows of seats without block. Rows without available seats are returned too, so that they still
+// separate the rows around them.
+func (s *Service) getBestSeatsRows(ctx context.Context, ids *domain.IDs, blockID string) ([]bestSeatsRow, error) {
+	query := &domain.SeatsQuery{
+		SeatsFilter: domain.SeatsFilter{OrgID: &ids.OrgID, SeatingPlanID: &ids.SplID},
+		Sort:        []domain.SeatSort{{Key: domain.SeatSortRow}, {Key: domain.SeatSortNum}},
+	}
+	if blockID != "" {
+		query.SeatBlockID = &blockID
+	}
+	page := domain.SeatsPageRequest{Limit: seatsPageSize}
+	var rows []bestSeatsRow
+	var rowSeatsCount int
+	for {
+		res, err := s.storage.GetSeatsPage(ctx, query, page)
+		if err != nil {
+			return nil, fmt.Errorf("error while querying available seats %w", err)
+		}
+		for _, seat := range res.Seats {
+			if seat.SeatRowID == nil || *seat.SeatRowID == "" {
+				continue
+			}
+			if blockID == "" && seat.SeatBlockID != nil && *seat.SeatBlockID != "" {
+				continue
+			}
+			if len(rows) == 0 || rows[len(rows)-1].rowID != *seat.SeatRowID {
+				row := bestSeatsRow{rowID: *seat.SeatRowID, blockID: blockID}
+				row.minX, row.maxX = float64(seat.X), float64(seat.X)
+				rows = append(rows, row)
+				rowSeatsCount = 0
+			}
+			row := &rows[len(rows)-1]
+			rowSeatsCount++
+			row.y += (float64(seat.Y) - row.y) / float64(rowSeatsCount)
+			row.minX = math.Min(row.minX, float64(seat.X))
+			row.maxX = math.Max(row.maxX, float64(seat.X))
+			if domain.SeatAvailableForOrder(seat) {
+				row.seats = append(row.seats, seat)
+			}
+		}
+		if res.NextCursor == nil {
+			return rows, nil
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
+// findBestRowBlock returns the best block of n adjacent seats which passes the seat rules,
+// linkedSeats are ids of seats linked with each seat
+func findBestRowBlock(rows []bestSeatsRow, linkedSeats map[string][]string, priceCategoryID string, n int, scorer SeatScorer) ([]domain.Seat, bool) {
+	block, ok := bestRowBlock(rows, linkedSeats, priceCategoryID, n, scorer)
+	return block.seats, ok
+}
+
+// bestRowBlock is findBestRowBlock returning the score of the block too
+func bestRowBlock(rows []bestSeatsRow, linkedSeats map[string][]string, priceCategoryID string, n int, scorer SeatScorer) (bestSeatsCandidate, bool) {
+	var candidates []bestSeatsCandidate
+	for _, row := range rows {
+		candidates = append(candidates, rowBlocks(row, priceCategoryID, n, scorer)...)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score < candidates[j].score
+	})
+	rowsByID := make(map[string]bestSeatsRow, len(rows))
+	for _, row := range rows {
+		rowsByID[row.rowID] = row
+	}
+	for _, candidate := range candidates {
+		row := rowsByID[*candidate.seats[0].SeatRowID]
+		if !keepsRowUnfragmented(row, candidate.seats) || !containsLinkedSeats(candidate.seats, linkedSeats) {
+			continue
+		}
+		return candidate, true
+	}
+	return bestSeatsCandidate{}, false
+}
+
+// findBestMultiRowBlock returns the best shape of n seats split into segments of adjacent seats in rowsCount
+// vertically adjacent rows of the same block, every segment keeps its row unfragmented. All splits of n seats which
+// fit into the rows are tried, e.g. 15+15 as well as 20+10 for 30 seats. Segments of the following rows are those
+// closest horizontally to the segment of the previous row which keep linked seats together. Shapes are scored
+// by the scores of their seats and by horizontal offsets between the segments weighted by offsetWeight.
+func findBestMultiRowBlock(rows []bestSeatsRow, linkedSeats map[string][]string, priceCategoryID string, n, rowsCount int, scorer SeatScorer, offsetWeight float64) ([]domain.Seat, bool) {
+	segments := make(map[bestSeatsSegmentKey][]bestSeatsCandidate)
+	rowSegments := func(row bestSeatsRow, size int) []bestSeatsCandidate {
+		key := bestSeatsSegmentKey{rowID: row.rowID, size: size}
+		if _, ok := segments[key]; !ok {
+			segments[key] = validRowBlocks(row, priceCategoryID, size, scorer)
+		}
+		return segments[key]
+	}
+	var best *bestSeatsCandidate
+	for _, blockRows := range rowsByBlocks(rows) {
+		for first := 0; first+rowsCount <= len(blockRows); first++ {
+			window := newBestSeatsWindow(blockRows[first:first+rowsCount], linkedSeats, rowSegments)
+			maxSizes := make([]int, len(window.rows))
+			for i, row := range window.rows {
+				maxSizes[i] = longestRowBlock(row, priceCategoryID)
+			}
+			for _, sizes := range rowSegmentSplits(n, maxSizes) {
+				for _, segment := range rowSegments(window.rows[0], sizes[0]) {
+					shape, ok := window.align([]bestSeatsCandidate{segment}, sizes)
+					if !ok {
+						continue
+					}
+					if candidate := shapeCandidate(shape, offsetWeight); best == nil || candidate.score < best.score {
+						best = &candidate
+					}
+				}
+			}
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return best.seats, true
+}
+
+// rowSegmentSplits returns all splits of n seats into segments of at least one seat, one segment for each row,
+// which are not bigger than maxSizes of the rows
+func rowSegmentSplits(n int, maxSizes []int) [][]int {
+	var splits [][]int
+	sizes := make([]int, len(maxSizes))
+	var split func(i, rest int)
+	split = func(i, rest int) {
+		if i == len(sizes)-1 {
+			if rest >= 1 && rest <= maxSizes[i] {
+				sizes[i] = rest
+				splits = append(splits, append([]int{}, sizes...))
+			}
+			return
+		}
+		// every following row needs at least one seat
+		for size := 1; size <= maxSizes[i] && size <= rest-(len(sizes)-1-i); size++ {
+			sizes[i] = size
+			split(i+1, rest-size)
+		}
+	}
+	if len(sizes) > 0 {
+		split(0, n)
+	}
+	return splits
+}
+
+// longestRowBlock returns the size of the longest block of eligible seats with consecutive numbers of the row
+func longestRowBlock(row bestSeatsRow, priceCategoryID string) int {
+	var longest, size int
+	for i, seat := range row.seats {
+		switch {
+		case !bestSeatsEligible(seat, priceCategoryID):
+			size = 0
+		case size > 0 && seat.Num == row.seats[i-1].Num+1:
+			size++
+		default:
+			size = 1
+		}
+		if size > longest {
+			longest = size
+		}
+	}
+	return longest
+}
+
+// rowsByBlocks groups rows by blocks, rows of a block are ordered by their vertical position
+func rowsByBlocks(rows []bestSeatsRow) [][]bestSeatsRow {
+	var blocks [][]bestSeatsRow
+	blockIndexes := make(map[string]int)
+	for _, row := range rows {
+		i, ok := blockIndexes[row.blockID]
+		if !ok {
+			i = len(blocks)
+			blockIndexes[row.blockID] = i
+			blocks = append(blocks, nil)
+		}
+		blocks[i] = append(blocks[i], row)
+	}
+	for _, blockRows := range blocks {
+		sort.SliceStable(blockRows, func(i, j int) bool {
+			return blockRows[i].y < blockRows[j].y
+		})
+	}
+	return blocks
+}
+
+// validRowBlocks returns blocks of the row which keep the row unfragmented
+func validRowBlocks(row bestSeatsRow, priceCategoryID string, n int, scorer SeatScorer) []bestSeatsCandidate {
+	var blocks []bestSeatsCandidate
+	for _, block := range rowBlocks(row, priceCategoryID, n, scorer) {
+		if keepsRowUnfragmented(row, block.seats) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// bestSeatsWindow are vertically adjacent rows of a block whose segments make a shape of best seats
+type bestSeatsWindow struct {
+	rows []bestSeatsRow
+	// seatRows are indexes of the rows by ids of their seats
+	seatRows    map[string]int
+	linkedSeats map[string][]string
+	segments    func(row bestSeatsRow, size int) []bestSeatsCandidate
+}
+
+func newBestSeatsWindow(rows []bestSeatsRow, linkedSeats map[string][]string, segments func(row bestSeatsRow, size int) []bestSeatsCandidate) *bestSeatsWindow {
+	w := &bestSeatsWindow{rows: rows, seatRows: make(map[string]int), linkedSeats: linkedSeats, segments: segments}
+	for i, row := range rows {
+		for _, seat := range row.seats {
+			w.seatRows[seat.ID] = i
+		}
+	}
+	return w
+}
+
+// align completes the shape with segments of sizes of the following rows. Segments closest horizontally to the
+// segment of the previous row are tried first, the search backtracks when a segment would split linked seats.
+func (w *bestSeatsWindow) align(shape []bestSeatsCandidate, sizes []int) ([]bestSeatsCandidate, bool) {
+	i := len(shape)
+	if !w.keepsLinkedSeats(shape) {
+		return nil, false
+	}
+	if i == len(w.rows) {
+		return shape, true
+	}
+	segments := w.segments(w.rows[i], sizes[i])
+	offset := func(segment bestSeatsCandidate) float64 {
+		return math.Abs(segment.centerX - shape[i-1].centerX)
+	}
+	// the closest segments are picked one by one, usually the first one completes the shape
+	tried := make([]bool, len(segments))
+	for {
+		closest := -1
+		for j := range segments {
+			if !tried[j] && (closest == -1 || offset(segments[j]) < offset(segments[closest])) {
+				closest = j
+			}
+		}
+		if closest == -1 {
+			return nil, false
+		}
+		tried[closest] = true
+		if aligned, ok := w.align(append(shape[:i:i], segments[closest]), sizes); ok {
+			return aligned, true
+		}
+	}
+}
+
+// keepsLinkedSeats tells whether the shape contains all seats linked with its seats which are in its rows, seats
+// linked with seats of the following rows may still be completed
+func (w *bestSeatsWindow) keepsLinkedSeats(shape []bestSeatsCandidate) bool {
+	seatIDs := make(map[string]bool)
+	for _, segment := range shape {
+		for _, seat := range segment.seats {
+			seatIDs[seat.ID] = true
+		}
+	}
+	for _, segment := range shape {
+		for _, seat := range segment.seats {
+			for _, linkedSeatID := range w.linkedSeats[seat.ID] {
+				row, ok := w.seatRows[linkedSeatID]
+				if !ok || (row < len(shape) && !seatIDs[linkedSeatID]) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// shapeCandidate scores the shape by the scores of its seats and by weighted horizontal offsets between its segments
+func shapeCandidate(shape []bestSeatsCandidate, offsetWeight float64) bestSeatsCandidate {
+	var seats []domain.Seat
+	var scoreSum, offset float64
+	for i, segment := range shape {
+		seats = append(seats, segment.seats...)
+		scoreSum += segment.score * float64(len(segment.seats))
+		if i > 0 {
+			offset += math.Abs(segment.centerX - shape[i-1].centerX)
+		}
+	}
+	return bestSeatsCandidate{seats: seats, score: scoreSum/float64(len(seats)) + offsetWeight*offset/float64(len(shape)-1)}
+}
+
+// seatsCenterX is the average horizontal position of the seats
+func seatsCenterX(seats []domain.Seat) float64 {
+	var sum float64
+	for _, seat := range seats {
+		sum += float64(seat.X)
+	}
+	return sum / float64(len(seats))
+}
+
+// rowBlocks returns all blocks of n eligible seats with consecutive numbers of the row scored by the scorer
+func rowBlocks(row bestSeatsRow, priceCategoryID string, n int, scorer SeatScorer) []bestSeatsCandidate {
+	var blocks []bestSeatsCandidate
+	start := 0
+	for i, seat := range row.seats {
+		switch {
+		case !bestSeatsEligible(seat, priceCategoryID):
+			start = i + 1
+			continue
+		case i > start && seat.Num != row.seats[i-1].Num+1:
+			start = i
+		}
+		if i-start+1 >= n {
+			block := row.seats[i-n+1 : i+1]
+			blocks = append(blocks, bestSeatsCandidate{seats: block, score: seatsScore(block, row, scorer), centerX: seatsCenterX(block)})
+		}
+	}
+	return blocks
+}
+
+// bestSeatsEligible tells whether the seat may be proposed as best available
+func bestSeatsEligible(seat domain.Seat, priceCategoryID string) bool {
+	if seat.Wheelchair {
+		return false
+	}
+	if priceCategoryID == "" {
+		return true
+	}
+	return seat.PriceCategoryID != nil && *seat.PriceCategoryID == priceCategoryID
+}
+
+// seatsScore is the average score of the row seats
+func seatsScore(seats []domain.Seat, row bestSeatsRow, scorer SeatScorer) float64 {
+	scoringRow := SeatScoringRow{RowID: row.rowID, BlockID: row.blockID, MinX: row.minX, MaxX: row.maxX}
+	var sum float64
+	for _, seat := range seats {
+		sum += scorer.ScoreSeat(seat, scoringRow)
+	}
+	return sum / float64(len(seats))
+}
+
+// keepsRowUnfragmented applies the fragmentation rule of ValidateSeatRules to the seats requested from the row
+func keepsRowUnfragmented(row bestSeatsRow, seats []domain.Seat) bool {
+	mappedRowSeats, err := mapAllRowSeatsForAvailability(row.seats, seats)
+	if err != nil {
+		return false
+	}
+	return checkMappedRowForFragmentation(mappedRowSeats) == nil
+}
+
+// linkedSeatsByGroups returns ids of seats linked with each seat of the groups
+func linkedSeatsByGroups(groups []domain.LinkedSeatGroup) map[string][]string {
+	linkedSeats := make(map[string][]string)
+	for _, group := range groups {
+		for _, seatID := range group.SeatIDs {
+			for _, linkedSeatID := range group.SeatIDs {
+				if linkedSeatID != seatID {
+					linkedSeats[seatID] = append(linkedSeats[seatID], linkedSeatID)
+				}
+			}
+		}
+	}
+	return linkedSeats
+}
+
+// containsLinkedSeats tells whether all seats linked with the seats are among them,
+// which means that the seats contain whole linked seat groups
+func containsLinkedSeats(seats []domain.Seat, linkedSeats map[string][]string) bool {
+	seatIDs := make(map[string]bool, len(seats))
+	for _, seat := range seats {
+		seatIDs[seat.ID] = true
+	}
+	for _, seat := range seats {
+		for _, linkedSeatID := range linkedSeats[seat.ID] {
+			if !seatIDs[linkedSeatID] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func toBestSeats(seats []domain.Seat) []domain.BestSeat {
+	bestSeats := make([]domain.BestSeat, len(seats))
+	for i, seat := range seats {
+		bestSeats[i] = domain.BestSeat{SeatID: seat.ID, SeatNum: int(seat.Num), SeatRowID: *seat.SeatRowID}
+	}
+	return bestSeats
+}
@@ -0,0 +1,516 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"sort"
+	"testing"
+	"time"
+)
+
+type SeatBestTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatBestTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatBestTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatBestTestSuite) TestFindBestSeatsPassesSeatRules() {
+	bestSeats, err := suite.service.FindBestSeats(suite.ctx, suite.ids(), "", 3)
+	suite.NoError(err)
+	suite.Len(bestSeats, 3)
+	requested := make([]domain.Seat, 0, len(bestSeats))
+	for i, bestSeat := range bestSeats {
+		suite.Equal(bestSeats[0].SeatRowID, bestSeat.SeatRowID)
+		suite.Equal(bestSeats[0].SeatNum+i, bestSeat.SeatNum)
+		seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: bestSeat.SeatID, OrgID: suite.org.ID})
+		suite.NoError(err)
+		requested = append(requested, *seat)
+	}
+	err = suite.service.ValidateSeatRules(suite.ctx, suite.org.ID, map[string]string{}, map[string]map[string][]domain.Seat{
+		suite.spl.ID: {bestSeats[0].SeatRowID: requested},
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatBestTestSuite) TestFindBestSeatsIncludesLinkLockedSeats() {
+	// linking locks seats[0], it's available for order together with seats[1]
+	linkedSeatID := suite.seats[1].ID
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: suite.seats[0].ID, LinkedSeatID: &linkedSeatID}}, time.Now())
+	suite.NoError(err)
+	var rowSeatIDs, otherSeatIDs []string
+	for _, seat := range suite.seats {
+		if *seat.SeatRowID == *suite.seats[0].SeatRowID {
+			rowSeatIDs = append(rowSeatIDs, seat.ID)
+		} else {
+			otherSeatIDs = append(otherSeatIDs, seat.ID)
+		}
+	}
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:        otherSeatIDs,
+		StatusCode:     domain.SeatStatusSold,
+		AddOrderID:     &orderID,
+		AddOrderItemID: &orderItemID,
+		UpdatedAt:      time.Now(),
+	})
+	suite.NoError(err)
+
+	// the whole row of the linked seats is the only block left
+	bestSeats, err := suite.service.FindBestSeats(suite.ctx, suite.ids(), "", len(rowSeatIDs))
+	suite.NoError(err)
+	requested := make([]domain.Seat, 0, len(bestSeats))
+	bestSeatIDs := make([]string, 0, len(bestSeats))
+	for _, bestSeat := range bestSeats {
+		seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: bestSeat.SeatID, OrgID: suite.org.ID})
+		suite.NoError(err)
+		requested = append(requested, *seat)
+		bestSeatIDs = append(bestSeatIDs, bestSeat.SeatID)
+	}
+	suite.ElementsMatch(rowSeatIDs, bestSeatIDs)
+	err = suite.service.ValidateSeatRules(suite.ctx, suite.org.ID, map[string]string{}, map[string]map[string][]domain.Seat{
+		suite.spl.ID: {*suite.seats[0].SeatRowID: requested},
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatBestTestSuite) TestFindBestSeatsNotFound() {
+	_, err := suite.service.FindBestSeats(suite.ctx, suite.ids(), "", 7)
+	suite.True(errors.Is(err, domain.ErrNoBestSeats))
+}
+
+func (suite *SeatBestTestSuite) TestFindBestRowBlockSkipsFragmentingBlock() {
+	// the closest block of seats 2 and 3 would leave seat 1 alone
+	row := bestSeatsTestRow("r1", 1, 6)
+	seats, ok := findBestRowBlock([]bestSeatsRow{row}, nil, "", 2, bestSeatsTestScorer(28))
+	suite.True(ok)
+	suite.Equal([]int32{3, 4}, bestSeatsTestNums(seats))
+}
+
+func (suite *SeatBestTestSuite) TestFindBestRowBlockKeepsLinkedSeatsTogether() {
+	row := bestSeatsTestRow("r1", 1, 6)
+	linkedSeats := linkedSeatsByGroups([]domain.LinkedSeatGroup{{SeatIDs: []string{row.seats[3].ID, row.seats[4].ID}}})
+	// seats 3 and 4 would split the linked seats 4 and 5
+	seats, ok := findBestRowBlock([]bestSeatsRow{row}, linkedSeats, "", 2, bestSeatsTestScorer(28))
+	suite.True(ok)
+	suite.Equal([]int32{1, 2}, bestSeatsTestNums(seats))
+
+	// seat 1 is linked with a seat of another row, so it can't be proposed and seats 2 and 3 would leave it alone
+	linkedSeats = linkedSeatsByGroups([]domain.LinkedSeatGroup{{SeatIDs: []string{row.seats[0].ID, "r2-1"}}})
+	seats, ok = findBestRowBlock([]bestSeatsRow{row}, linkedSeats, "", 2, bestSeatsTestScorer(0))
+	suite.True(ok)
+	suite.Equal([]int32{3, 4}, bestSeatsTestNums(seats))
+}
+
+func (suite *SeatBestTestSuite) TestFindBestRowBlockSkipsIneligibleSeats() {
+	pcID, otherPcID := "pc1", "pc2"
+	row := bestSeatsTestRow("r1", 1, 6)
+	for i := range row.seats {
+		row.seats[i].PriceCategoryID = &pcID
+	}
+	row.seats[2].PriceCategoryID = &otherPcID
+	row.seats[3].Wheelchair = true
+	_, ok := findBestRowBlock([]bestSeatsRow{row}, nil, pcID, 3, bestSeatsTestScorer(38))
+	suite.False(ok)
+	seats, ok := findBestRowBlock([]bestSeatsRow{row}, nil, pcID, 2, bestSeatsTestScorer(38))
+	suite.True(ok)
+	suite.Equal([]int32{5, 6}, bestSeatsTestNums(seats))
+}
+
+func (suite *SeatBestTestSuite) TestFindBestSeatsAcrossRowsPrefersOneRow() {
+	bestSeats, err := suite.service.FindBestSeatsAcrossRows(suite.ctx, suite.ids(), "", 4, 2)
+	suite.NoError(err)
+	suite.Len(bestSeats, 4)
+	for _, bestSeat := range bestSeats {
+		suite.Equal(bestSeats[0].SeatRowID, bestSeat.SeatRowID)
+	}
+}
+
+func (suite *SeatBestTestSuite) TestFindBestMultiRowBlockAlignsSegments() {
+	front := bestSeatsTestRow("r1", 1, 5)
+	back := bestSeatsTestRow("r2", 1, 8)
+	back.y = 10
+	// seat 1 of the back row is sold and its seats 2 to 6 are right behind seats 1 to 5 of the front row,
+	// seats 4 to 8 would be valid too, but they are further to the side
+	back.seats = back.seats[1:]
+	for i := range back.seats {
+		back.seats[i].X -= 10
+	}
+	seats, ok := findBestMultiRowBlock([]bestSeatsRow{back, front}, nil, "", 10, 2, bestSeatsTestScorer(0), 1)
+	suite.True(ok)
+	suite.Equal([]int32{1, 2, 3, 4, 5, 2, 3, 4, 5, 6}, bestSeatsTestNums(seats))
+	suite.Equal("r1", *seats[0].SeatRowID)
+	suite.Equal("r2", *seats[5].SeatRowID)
+}
+
+func (suite *SeatBestTestSuite) TestFindBestMultiRowBlockUsesOnlyAdjacentRowsOfBlock() {
+	front := bestSeatsTestRow("r1", 1, 4)
+	middle := bestSeatsTestRow("r2", 1, 4)
+	middle.y, middle.seats = 10, nil
+	back := bestSeatsTestRow("r3", 1, 4)
+	back.y = 20
+	_, ok := findBestMultiRowBlock([]bestSeatsRow{front, middle, back}, nil, "", 8, 2, bestSeatsTestScorer(0), 1)
+	suite.False(ok)
+	middle.blockID = "b2"
+	seats, ok := findBestMultiRowBlock([]bestSeatsRow{front, middle, back}, nil, "", 8, 2, bestSeatsTestScorer(0), 1)
+	suite.True(ok)
+	suite.Len(seats, 8)
+}
+
+func (suite *SeatBestTestSuite) TestFindBestMultiRowBlockSplitsUnevenly() {
+	front := bestSeatsTestRow("r1", 1, 20)
+	back := bestSeatsTestRow("r2", 1, 10)
+	back.y = 10
+	seats, ok := findBestMultiRowBlock([]bestSeatsRow{front, back}, nil, "", 30, 2, bestSeatsTestScorer(0), 1)
+	suite.True(ok)
+	suite.Len(seats, 30)
+	suite.Equal("r1", *seats[19].SeatRowID)
+	suite.Equal("r2", *seats[20].SeatRowID)
+}
+
+func (suite *SeatBestTestSuite) TestFindBestMultiRowBlockBacktracksLinkedSeats() {
+	front := bestSeatsTestRow("r1", 1, 4)
+	back := bestSeatsTestRow("r2", 1, 10)
+	back.y = 10
+	// seats 5 and 6 of the back row are sold
+	back.seats = append(back.seats[:4:4], back.seats[6:]...)
+	// seats 1 to 4 of the back row are the closest, but seat 1 is linked with a seat of another row
+	linkedSeats := linkedSeatsByGroups([]domain.LinkedSeatGroup{{SeatIDs: []string{back.seats[0].ID, "r3-1"}}})
+	seats, ok := findBestMultiRowBlock([]bestSeatsRow{front, back}, linkedSeats, "", 8, 2, bestSeatsTestScorer(0), 1)
+	suite.True(ok)
+	suite.Equal([]int32{1, 2, 3, 4, 7, 8, 9, 10}, bestSeatsTestNums(seats))
+}
+
+func (suite *SeatBestTestSuite) TestFindBestMultiRowBlockWeightsOffsets() {
+	front := bestSeatsTestRow("r1", 1, 4)
+	middle := bestSeatsTestRow("r2", 1, 4)
+	back := bestSeatsTestRow("r3", 11, 14)
+	middle.y, back.y = 10, 20
+	for i := range middle.seats {
+		middle.seats[i].Y, back.seats[i].Y = 10, 20
+	}
+	// the back row is closer to the stage on the right, but it's far from the middle row
+	rows := []bestSeatsRow{front, middle, back}
+	seats, ok := findBestMultiRowBlock(rows, nil, "", 8, 2, bestSeatsTestScorer(140), 0)
+	suite.True(ok)
+	suite.Equal("r2", *seats[0].SeatRowID)
+	suite.Equal("r3", *seats[4].SeatRowID)
+	seats, ok = findBestMultiRowBlock(rows, nil, "", 8, 2, bestSeatsTestScorer(140), 1)
+	suite.True(ok)
+	suite.Equal("r1", *seats[0].SeatRowID)
+	suite.Equal("r2", *seats[4].SeatRowID)
+}
+
+func (suite *SeatBestTestSuite) TestRowSegmentSplits() {
+	suite.Equal([][]int{{20, 10}}, rowSegmentSplits(30, []int{20, 10}))
+	suite.Equal([][]int{{1, 3}, {2, 2}, {3, 1}}, rowSegmentSplits(4, []int{3, 3}))
+	suite.Equal([][]int{{1, 1, 2}, {1, 2, 1}, {2, 1, 1}}, rowSegmentSplits(4, []int{2, 2, 2}))
+	suite.Empty(rowSegmentSplits(7, []int{3, 3}))
+}
+
+func (suite *SeatBestTestSuite) TestFindBestSeatsInBatches() {
+	err := suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), &domain.SeatScoringConfig{
+		DistanceWeight:  1,
+		ReferencePoints: []domain.SeatReferencePoint{{Name: "stage", X: suite.seats[0].X, Y: suite.seats[0].Y, Weight: 1}},
+	})
+	suite.NoError(err)
+	// batches of 2 seats make the search pull several batches
+	bestSeats, err := suite.service.findBestSeats(suite.ctx, suite.ids(), "", 3, 2)
+	suite.NoError(err)
+	suite.Len(bestSeats, 3)
+	for i, bestSeat := range bestSeats {
+		suite.Equal(bestSeats[0].SeatRowID, bestSeat.SeatRowID)
+		suite.Equal(bestSeats[0].SeatNum+i, bestSeat.SeatNum)
+	}
+}
+
+func (suite *SeatBestTestSuite) TestFindBestRowBlockByCandidatesLoadsOnlyNeededBlocks() {
+	plan := newBestSeatsTestPlan()
+	blocks := plan.blocks()
+	seats, err := findBestRowBlockByCandidates(suite.ctx, plan.candidates(), blocks, bestSeatsTestCheap, 6, bestSeatsTestConfig())
+	suite.NoError(err)
+	best, ok := findBestRowBlock(plan.rows, nil, bestSeatsTestCheap, 6, bestSeatsTestScorer(0))
+	suite.True(ok)
+	suite.Equal(bestSeatsTestIDs(best), bestSeatsTestIDs(seats))
+	// the first blocks have no cheap seats
+	suite.Equal([]string{"b7", "b8", "b9"}, plan.loaded)
+
+	plan.loaded = nil
+	seats, err = findBestRowBlockByCandidates(suite.ctx, plan.candidates(), plan.blocks(), "", 6, bestSeatsTestConfig())
+	suite.NoError(err)
+	suite.Equal("r0", *seats[0].SeatRowID)
+	suite.Equal([]int32{1, 2, 3, 4, 5, 6}, bestSeatsTestNums(seats))
+	// further seats can't score better than the first row
+	suite.Equal([]string{"b0"}, plan.loaded)
+
+	_, err = findBestRowBlockByCandidates(suite.ctx, plan.candidates(), plan.blocks(), bestSeatsTestCheap, 301, bestSeatsTestConfig())
+	suite.ErrorIs(err, domain.ErrNoBestSeats)
+}
+
+func (suite *SeatBestTestSuite) TestFindBestRowBlockByCandidatesAppliesScoringConfig() {
+	plan := newBestSeatsTestPlan()
+	config := bestSeatsTestConfig()
+	config.RowWeight = 1
+	config.RowPreferences = map[string]float64{"r42": -10000}
+	seats, err := findBestRowBlockByCandidates(suite.ctx, plan.candidates(), plan.blocks(), "", 6, config)
+	suite.NoError(err)
+	suite.Equal("r42", *seats[0].SeatRowID)
+	// the preferred row makes the search load the blocks up to its block
+	suite.Equal([]string{"b0", "b1", "b2"}, plan.loaded)
+}
+
+func (suite *SeatBestTestSuite) TestGetBestSeatsByReferencePoints() {
+	// the seats of the other rows are sold by TestFindBestSeatsIncludesLinkLockedSeats
+	left, right := suite.seats[0], suite.seats[5]
+	err := suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), &domain.SeatScoringConfig{
+		DistanceWeight: 1,
+		ReferencePoints: []domain.SeatReferencePoint{
+			{Name: "left", X: left.X, Y: left.Y, Weight: 1},
+			{Name: "right", X: right.X, Y: right.Y, Weight: 1},
+		},
+	})
+	suite.NoError(err)
+	bestSeats, err := suite.service.storage.GetBestSeatsByReferencePoints(suite.ctx, suite.ids(), "", 100)
+	suite.NoError(err)
+	suite.Len(bestSeats, 6)
+	if suite.GreaterOrEqual(len(bestSeats), 2) {
+		suite.ElementsMatch([]string{left.ID, right.ID}, []string{bestSeats[0].SeatID, bestSeats[1].SeatID})
+	}
+	bestSeats, err = suite.service.storage.GetBestSeatsByReferencePoints(suite.ctx, suite.ids(), "", 3)
+	suite.NoError(err)
+	suite.Len(bestSeats, 3)
+
+	bestSeats, err = suite.service.storage.GetBestSeatsByReferencePoints(suite.ctx, &domain.IDs{OrgID: suite.org.ID, SplID: idgen.New("spl")}, "", 100)
+	suite.NoError(err)
+	suite.Empty(bestSeats)
+}
+
+// TestBestSeatsByRowCentrality runs before the seating plan of the suite has a config and before its seats are sold
+func (suite *SeatBestTestSuite) TestBestSeatsByRowCentrality() {
+	bestSeats, err := suite.service.storage.GetBestSeatsByReferencePoints(suite.ctx, suite.ids(), "", 100)
+	suite.NoError(err)
+	suite.Len(bestSeats, len(suite.seats))
+	if suite.NotEmpty(bestSeats) {
+		// the middle seats of the rows of 6 seats are the closest to the center
+		suite.Contains([]string{suite.seats[2].ID, suite.seats[3].ID, suite.seats[8].ID, suite.seats[9].ID}, bestSeats[0].SeatID)
+	}
+}
+
+// bestSeatsTestRow returns available seats numbered from first to last placed 10 units apart
+func bestSeatsTestRow(rowID string, first, last int32) bestSeatsRow {
+	row := bestSeatsRow{rowID: rowID}
+	for num := first; num <= last; num++ {
+		row.seats = append(row.seats, domain.Seat{
+			ID:        fmt.Sprintf("%s-%d", rowID, num),
+			Num:       num,
+			X:         num * 10,
+			SeatRowID: &row.rowID,
+		})
+	}
+	row.minX, row.maxX = float64(first*10), float64(last*10)
+	return row
+}
+
+// bestSeatsTestScorer scores seats by their distance from the stage at x
+func bestSeatsTestScorer(x int32) SeatScorer {
+	return DistanceScorer{{Name: "stage", X: x, Weight: 1}}
+}
+
+func bestSeatsTestNums(seats []domain.Seat) []int32 {
+	nums := make([]int32, len(seats))
+	for i, seat := range seats {
+		nums[i] = seat.Num
+	}
+	return nums
+}
+
+func bestSeatsTestIDs(seats []domain.Seat) []string {
+	seatIDs := make([]string, len(seats))
+	for i, seat := range seats {
+		seatIDs[i] = seat.ID
+	}
+	return seatIDs
+}
+
+// bestSeatsTestConfig scores seats like bestSeatsTestScorer(0)
+func bestSeatsTestConfig() *domain.SeatScoringConfig {
+	return &domain.SeatScoringConfig{
+		DistanceWeight:  1,
+		ReferencePoints: []domain.SeatReferencePoint{{Name: "stage", Weight: 1}},
+	}
+}
+
+const bestSeatsTestCheap = "cheap"
+
+// bestSeatsTestPlan is a synthetic plan of 200 rows of 300 seats in blocks of 20 rows. The last 50 rows are of
+// the cheap price category, every third of their seats is sold except for the last row, so that the nearest valid
+// cheap block is in the last row.
+type bestSeatsTestPlan struct {
+	rows    []bestSeatsRow
+	byBlock map[string][]bestSeatsRow
+	sorted  []domain.Seat
+	// loaded are ids of the blocks loaded by the blocks of the plan
+	loaded []string
+}
+
+func newBestSeatsTestPlan() *bestSeatsTestPlan {
+	expensive, cheap := "expensive", bestSeatsTestCheap
+	plan := &bestSeatsTestPlan{byBlock: make(map[string][]bestSeatsRow)}
+	for r := int32(0); r < 200; r++ {
+		row := bestSeatsRow{rowID: fmt.Sprintf("r%d", r), blockID: fmt.Sprintf("b%d", r/20), y: float64(r * 10), maxX: 3000}
+		pcID := &expensive
+		if r >= 150 {
+			pcID = &cheap
+		}
+		for num := int32(1); num <= 300; num++ {
+			if r >= 150 && r < 199 && num%3 == 0 {
+				continue
+			}
+			row.seats = append(row.seats, domain.Seat{
+				ID:              fmt.Sprintf("%s-%d", row.rowID, num),
+				Num:             num,
+				X:               num * 10,
+				Y:               r * 10,
+				PriceCategoryID: pcID,
+				SeatRowID:       &row.rowID,
+				SeatBlockID:     &row.blockID,
+			})
+		}
+		plan.rows = append(plan.rows, row)
+		plan.byBlock[row.blockID] = append(plan.byBlock[row.blockID], row)
+		plan.sorted = append(plan.sorted, row.seats...)
+	}
+	scorer := bestSeatsTestScorer(0)
+	sort.SliceStable(plan.sorted, func(i, j int) bool {
+		return scorer.ScoreSeat(plan.sorted[i], SeatScoringRow{}) < scorer.ScoreSeat(plan.sorted[j], SeatScoringRow{})
+	})
+	return plan
+}
+
+func (p *bestSeatsTestPlan) candidates() bestSeatsCandidates {
+	return &bestSeatsTestCandidates{seats: p.sorted, i: -1, scorer: bestSeatsTestScorer(0)}
+}
+
+func (p *bestSeatsTestPlan) blocks() *bestSeatsBlocks {
+	return &bestSeatsBlocks{load: func(_ context.Context, blockID string) ([]bestSeatsRow, map[string][]string, error) {
+		p.loaded = append(p.loaded, blockID)
+		return p.byBlock[blockID], nil, nil
+	}}
+}
+
+type bestSeatsTestCandidates struct {
+	seats  []domain.Seat
+	i      int
+	scorer SeatScorer
+}
+
+func (c *bestSeatsTestCandidates) Next(context.Context) bool {
+	c.i++
+	return c.i < len(c.seats)
+}
+
+func (c *bestSeatsTestCandidates) Seat() domain.Seat {
+	return c.seats[c.i]
+}
+
+func (c *bestSeatsTestCandidates) Distance() float64 {
+	return c.scorer.ScoreSeat(c.seats[c.i], SeatScoringRow{})
+}
+
+func (c *bestSeatsTestCandidates) Err() error {
+	return nil
+}
+
+func BenchmarkFindBestRowBlockByCandidates(b *testing.B) {
+	plan := newBestSeatsTestPlan()
+	config := bestSeatsTestConfig()
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := findBestRowBlockByCandidates(ctx, plan.candidates(), plan.blocks(), bestSeatsTestCheap, 6, config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindBestRowBlock(b *testing.B) {
+	plan := newBestSeatsTestPlan()
+	scorer := bestSeatsTestScorer(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := findBestRowBlock(plan.rows, nil, bestSeatsTestCheap, 6, scorer); !ok {
+			b.Fatal("no best seats found")
+		}
+	}
+}
+
+func TestSeatBestTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatBestTestSuite))
+}
+
+// SeatBestBenchmarkSuite benchmarks best seats of a stadium sized seating plan stored in the database
+type SeatBestBenchmarkSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatBestBenchmarkSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(60000, 300)
+	stage := suite.seats[0]
+	err := suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), &domain.SeatScoringConfig{
+		DistanceWeight:  1,
+		ReferencePoints: []domain.SeatReferencePoint{{Name: "stage", X: stage.X, Y: stage.Y, Weight: 1}},
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatBestBenchmarkSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatBestBenchmarkSuite) TestListSeatsByDistance() {
+	config, err := suite.service.storage.GetSeatScoringConfig(suite.ctx, suite.ids())
+	suite.NoError(err)
+	res := testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			// pulls several batches, so that the keyset of the next batch is measured too
+			it := suite.service.storage.IterateBestSeatsByPosition(suite.ids(), "", config.ReferencePoints, 100)
+			for pulled := 0; pulled < 500 && it.Next(suite.ctx); pulled++ {
+			}
+			if err := it.Err(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	suite.T().Logf("ListSeatsByDistance of %d seats: %s %s", len(suite.seats), res, res.MemString())
+}
+
+func (suite *SeatBestBenchmarkSuite) TestFindBestSeats() {
+	res := testing.Benchmark(func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := suite.service.FindBestSeats(suite.ctx, suite.ids(), "", 6); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	suite.T().Logf("FindBestSeats of %d seats: %s %s", len(suite.seats), res, res.MemString())
+}
+
+// TestSeatBestBenchmarkSuite runs only with -bench, e.g. go test -run TestSeatBestBenchmarkSuite -bench X
+func TestSeatBestBenchmarkSuite(t *testing.T) {
+	if flag.Lookup("test.bench").Value.String() == "" {
+		t.Skip("best seats benchmarks run with -bench")
+	}
+	suite.Run(t, new(SeatBestBenchmarkSuite))
+}
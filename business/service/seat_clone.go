@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/proj/business/domain"
+)
+
+// CloneSeatingPlanSeats copies seats of the source seating plan to the destination one,
+// it returns ids of the copies by ids of the source seats. Seat groups and price categories of the copies
+// are the ones of the destination seating plan with the same names, the seats aren't copied when a name
+// has none or several counterparts.
+func (s *Service) CloneSeatingPlanSeats(ctx context.Context, ids *domain.IDs, srcSeatingPlanID, dstSeatingPlanID string) (map[string]string, error) {
+	refs, err := s.getSeatCloneRefs(ctx, ids.OrgID, srcSeatingPlanID, dstSeatingPlanID)
+	if err != nil {
+		return nil, err
+	}
+	clonedSeatIDs, err := s.storage.CloneSeatingPlanSeats(ctx, ids, srcSeatingPlanID, dstSeatingPlanID, refs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error while cloning seating plan seats %w", err)
+	}
+	return clonedSeatIDs, nil
+}
+
+// getSeatCloneRefs maps seat groups and price categories of the source seating plan to their counterparts
+// in the destination seating plan
+func (s *Service) getSeatCloneRefs(ctx context.Context, orgID, srcSeatingPlanID, dstSeatingPlanID string) (domain.SeatCloneRefs, error) {
+	refs := make(domain.SeatCloneRefs)
+	names := make(map[string]map[string]string, 2)
+	for _, seatingPlanID := range []string{srcSeatingPlanID, dstSeatingPlanID} {
+		sgs, err := s.GetSeatGroups(ctx, &domain.SeatGroupsFilter{OrgID: &orgID, SeatingPlanID: &seatingPlanID})
+		if err != nil {
+			return nil, fmt.Errorf("error while getting seat groups %w", err)
+		}
+		names[seatingPlanID] = make(map[string]string, len(sgs))
+		for _, sg := range sgs {
+			names[seatingPlanID][sg.ID] = sg.Name
+		}
+	}
+	addSeatCloneRefs(refs, names[srcSeatingPlanID], names[dstSeatingPlanID])
+	for _, seatingPlanID := range []string{srcSeatingPlanID, dstSeatingPlanID} {
+		pcs, err := s.GetPriceCategories(ctx, &domain.PriceCategoriesFilter{OrgID: &orgID, SeatingPlanID: &seatingPlanID})
+		if err != nil {
+			return nil, fmt.Errorf("error while getting price categories %w", err)
+		}
+		names[seatingPlanID] = make(map[string]string, len(pcs))
+		for _, pc := range pcs {
+			names[seatingPlanID][pc.ID] = pc.Name
+		}
+	}
+	addSeatCloneRefs(refs, names[srcSeatingPlanID], names[dstSeatingPlanID])
+	return refs, nil
+}
+
+// addSeatCloneRefs maps source ids to the destination ids with the same name, names used several times
+// in the destination are ambiguous and left out
+func addSeatCloneRefs(refs domain.SeatCloneRefs, srcNames, dstNames map[string]string) {
+	dstIDs := make(map[string][]string, len(dstNames))
+	for id, name := range dstNames {
+		dstIDs[name] = append(dstIDs[name], id)
+	}
+	for id, name := range srcNames {
+		if len(dstIDs[name]) == 1 {
+			refs[id] = dstIDs[name][0]
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatCloneTestSuite struct {
+	CommonSuite
+	srcSpl   *domain.SeatingPlan
+	srcSeats []domain.Seat
+	dstSpl   *domain.SeatingPlan
+}
+
+func (suite *SeatCloneTestSuite) SetupSuite() {
+	suite.SetupOrg()
+	suite.srcSpl, _, suite.srcSeats = suite.CreateSeatingPlanEventWithSeatGroups(12, 6)
+	// the destination has the same seat groups and price categories as the source but no seats
+	var dstSeats []domain.Seat
+	suite.dstSpl, _, dstSeats = suite.CreateSeatingPlanEventWithSeatGroups(12, 6)
+	dstSeatIDs := make([]string, len(dstSeats))
+	for i, seat := range dstSeats {
+		dstSeatIDs[i] = seat.ID
+	}
+	err := suite.service.storage.DeleteSeats(suite.ctx, &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID, SplID: suite.dstSpl.ID}, dstSeatIDs, time.Now())
+	suite.NoError(err)
+
+	ids := &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID, SplID: suite.srcSpl.ID}
+	linkedSeatID := suite.srcSeats[1].ID
+	err = suite.service.storage.UpdateSeats(suite.ctx, ids, []*domain.UpdateSeat{{ID: suite.srcSeats[0].ID, LinkedSeatID: &linkedSeatID}}, time.Now())
+	suite.NoError(err)
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, ids, domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.srcSeats[2].ID},
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatCloneTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatCloneTestSuite) TestCloneSeatingPlanSeats() {
+	ids := &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID}
+	clonedSeatIDs, err := suite.service.CloneSeatingPlanSeats(suite.ctx, ids, suite.srcSpl.ID, suite.dstSpl.ID)
+	suite.NoError(err)
+	suite.Len(clonedSeatIDs, len(suite.srcSeats))
+
+	dstSeats, err := suite.service.storage.GetSeatsBySeatingPlanID(suite.ctx, &domain.IDs{OrgID: suite.org.ID, SplID: suite.dstSpl.ID})
+	suite.NoError(err)
+	suite.Len(dstSeats, len(suite.srcSeats))
+	srcGroupNames, dstGroupNames := suite.seatGroupNames(suite.srcSpl.ID), suite.seatGroupNames(suite.dstSpl.ID)
+	srcPriceCategoryNames, dstPriceCategoryNames := suite.priceCategoryNames(suite.srcSpl.ID), suite.priceCategoryNames(suite.dstSpl.ID)
+	dstSeatsByID := make(map[string]domain.Seat, len(dstSeats))
+	for _, seat := range dstSeats {
+		dstSeatsByID[seat.ID] = seat
+	}
+	for _, srcSeat := range suite.srcSeats {
+		dstSeat, ok := dstSeatsByID[clonedSeatIDs[srcSeat.ID]]
+		suite.True(ok)
+		suite.Equal(srcSeat.Name, dstSeat.Name)
+		suite.Equal(srcSeat.Num, dstSeat.Num)
+		suite.Equal(srcSeat.X, dstSeat.X)
+		suite.Equal(srcSeat.Y, dstSeat.Y)
+		// the copies reference seat groups and price categories of the destination seating plan
+		if suite.NotNil(dstSeat.SeatRowID) {
+			suite.Contains(dstGroupNames, *dstSeat.SeatRowID)
+			suite.Equal(srcGroupNames[*srcSeat.SeatRowID], dstGroupNames[*dstSeat.SeatRowID])
+		}
+		if srcSeat.PriceCategoryID != nil && suite.NotNil(dstSeat.PriceCategoryID) {
+			suite.Contains(dstPriceCategoryNames, *dstSeat.PriceCategoryID)
+			suite.Equal(srcPriceCategoryNames[*srcSeat.PriceCategoryID], dstPriceCategoryNames[*dstSeat.PriceCategoryID])
+		}
+		suite.Empty(dstSeat.OrderID)
+		if srcSeat.ID != suite.srcSeats[0].ID {
+			suite.Equal(domain.SeatStatusAvailable, dstSeat.StatusCode)
+		}
+	}
+	clonedSeat := dstSeatsByID[clonedSeatIDs[suite.srcSeats[0].ID]]
+	suite.Equal(clonedSeatIDs[suite.srcSeats[1].ID], clonedSeat.LinkedSeatID)
+	suite.Equal(domain.SeatStatusLocked, clonedSeat.StatusCode)
+
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{OrgID: suite.org.ID, SeatID: &clonedSeat.ID}, 10)
+	suite.NoError(err)
+	suite.Len(page.Logs, 1)
+	suite.Equal(fmt.Sprintf("created (cloned from %s)", suite.srcSpl.ID), page.Logs[0].Action)
+}
+
+func (suite *SeatCloneTestSuite) TestCloneSeatingPlanSeatsToAnotherOrgPlan() {
+	ids := &domain.IDs{OrgID: idgen.New("org"), UserID: suite.user.ID}
+	_, err := suite.service.CloneSeatingPlanSeats(suite.ctx, ids, suite.srcSpl.ID, suite.dstSpl.ID)
+	suite.ErrorIs(err, domain.ErrSeatCloneDestinationNotFound)
+
+	ids = &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID}
+	_, err = suite.service.CloneSeatingPlanSeats(suite.ctx, ids, suite.srcSpl.ID, idgen.New("spl"))
+	suite.ErrorIs(err, domain.ErrSeatCloneDestinationNotFound)
+}
+
+func (suite *SeatCloneTestSuite) TestSeatCloneRefsLeaveOutMissingAndAmbiguousNames() {
+	refs := make(domain.SeatCloneRefs)
+	addSeatCloneRefs(refs,
+		map[string]string{"sg-src-1": "1", "sg-src-2": "2", "sg-src-a": "A"},
+		map[string]string{"sg-dst-1": "1", "sg-dst-a": "A", "sg-dst-gate-a": "A"},
+	)
+	suite.Equal(domain.SeatCloneRefs{"sg-src-1": "sg-dst-1"}, refs)
+}
+
+func (suite *SeatCloneTestSuite) TestCloneSeatingPlanSeatsToItself() {
+	ids := &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID}
+	_, err := suite.service.CloneSeatingPlanSeats(suite.ctx, ids, suite.srcSpl.ID, suite.srcSpl.ID)
+	suite.Error(err)
+}
+
+func (suite *SeatCloneTestSuite) seatGroupNames(seatingPlanID string) map[string]string {
+	sgs, err := suite.service.GetSeatGroups(suite.ctx, &domain.SeatGroupsFilter{OrgID: &suite.org.ID, SeatingPlanID: &seatingPlanID})
+	suite.NoError(err)
+	names := make(map[string]string, len(sgs))
+	for _, sg := range sgs {
+		names[sg.ID] = sg.Name
+	}
+	return names
+}
+
+func (suite *SeatCloneTestSuite) priceCategoryNames(seatingPlanID string) map[string]string {
+	pcs, err := suite.service.GetPriceCategories(suite.ctx, &domain.PriceCategoriesFilter{OrgID: &suite.org.ID, SeatingPlanID: &seatingPlanID})
+	suite.NoError(err)
+	names := make(map[string]string, len(pcs))
+	for _, pc := range pcs {
+		names[pc.ID] = pc.Name
+	}
+	return names
+}
+
+func TestSeatCloneTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatCloneTestSuite))
+}
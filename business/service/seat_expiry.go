@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/proj/business/domain"
+	"github.com/proj/business/storage"
+)
+
+// DefaultSeatExpiryInterval is the pause between two runs of the worker when SeatExpiryConfig.Interval is not set
+const DefaultSeatExpiryInterval = time.Minute
+
+// DefaultSeatOfferExpiry is the time after which offered seats are released when SeatExpiryConfig.OfferExpiry
+// is not set
+const DefaultSeatOfferExpiry = domain.DefaultSeatOfferExpiry
+
+// SeatExpiryConfig configures the seat expiry worker
+type SeatExpiryConfig struct {
+	// Interval is the pause between two runs of the worker, DefaultSeatExpiryInterval is used when not set
+	Interval time.Duration
+	// OfferExpiry is the time after which offered seats are released, DefaultSeatOfferExpiry is used when not set
+	OfferExpiry time.Duration
+	// OrgOfferExpiry overrides OfferExpiry for the given org ids, offered seats of orgs overridden with
+	// a non-positive expiry are never released
+	OrgOfferExpiry map[string]time.Duration
+	// OnReport is called after every successful run
+	OnReport func(domain.SeatExpiryReport)
+	// OnError is called when a run fails, the worker keeps running
+	OnError func(error)
+}
+
+// SeatExpiryWorker periodically releases offered and held seats which have expired and wheelchair spaces
+// of events starting soon
+type SeatExpiryWorker struct {
+	storage *storage.Storage
+	cfg     SeatExpiryConfig
+	now     func() time.Time
+}
+
+// NewSeatExpiryWorker returns a seat expiry worker, now is used as the clock of the worker and defaults to time.Now
+func (s *Service) NewSeatExpiryWorker(cfg SeatExpiryConfig, now func() time.Time) *SeatExpiryWorker {
+	if now == nil {
+		now = time.Now
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultSeatExpiryInterval
+	}
+	if cfg.OfferExpiry <= 0 {
+		cfg.OfferExpiry = DefaultSeatOfferExpiry
+	}
+	return &SeatExpiryWorker{storage: s.storage, cfg: cfg, now: now}
+}
+
+// Run releases expired seats every interval until ctx is done
+func (w *SeatExpiryWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		report, err := w.RunOnce(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if w.cfg.OnError != nil {
+				w.cfg.OnError(err)
+			}
+		} else if w.cfg.OnReport != nil {
+			w.cfg.OnReport(report)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce releases expired offered seats of every org and expired seat holds, wheelchair spaces are released
+// to general sale according to the accessibility rules of their org
+func (w *SeatExpiryWorker) RunOnce(ctx context.Context) (domain.SeatExpiryReport, error) {
+	now := w.now()
+	report := domain.SeatExpiryReport{RunAt: now, ReleasedPerSeatingPlan: make(map[string]int)}
+
+	orgIDs, err := w.storage.GetOrgIDsWithOfferedSeats(ctx)
+	if err != nil {
+		return report, fmt.Errorf("error while querying orgs with offered seats %w", err)
+	}
+	for _, orgID := range orgIDs {
+		offerExpiry := w.offerExpiry(orgID)
+		if offerExpiry <= 0 {
+			continue
+		}
+		offeredBefore := now.Add(-offerExpiry)
+		released, err := w.storage.ReleaseExpiredOfferedSeats(ctx, &domain.IDs{OrgID: orgID}, offeredBefore, now)
+		if err != nil {
+			return report, fmt.Errorf("error while releasing offered seats of org %v %w", orgID, err)
+		}
+		countReleasedSeats(report.ReleasedPerSeatingPlan, released)
+	}
+
+	released, err := w.storage.ReleaseExpiredSeatHolds(ctx, now)
+	if err != nil {
+		return report, fmt.Errorf("error while releasing seat holds %w", err)
+	}
+	countReleasedSeats(report.ReleasedPerSeatingPlan, released)
+
+	releasedWheelchairSeats, err := w.storage.ReleaseWheelchairSeats(ctx, now)
+	if err != nil {
+		return report, fmt.Errorf("error while releasing wheelchair seats %w", err)
+	}
+	report.WheelchairSeatsReleased = len(releasedWheelchairSeats)
+
+	return report, nil
+}
+
+func (w *SeatExpiryWorker) offerExpiry(orgID string) time.Duration {
+	if expiry, ok := w.cfg.OrgOfferExpiry[orgID]; ok {
+		return expiry
+	}
+	return w.cfg.OfferExpiry
+}
+
+func countReleasedSeats(releasedPerSeatingPlan map[string]int, released []domain.ReleasedSeat) {
+	for _, rs := range released {
+		releasedPerSeatingPlan[rs.SeatingPlanID]++
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatExpiryTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+	now   time.Time
+}
+
+func (suite *SeatExpiryTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+func (suite *SeatExpiryTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatExpiryTestSuite) offerSeats(seats []domain.Seat, offeredAt time.Time) {
+	offered := int32(domain.SeatStatusOffered)
+	updates := make([]*domain.UpdateSeat, len(seats))
+	for i, seat := range seats {
+		updates[i] = &domain.UpdateSeat{ID: seat.ID, StatusCode: &offered, OfferedAt: &offeredAt}
+	}
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), updates, offeredAt)
+	suite.NoError(err)
+}
+
+func (suite *SeatExpiryTestSuite) seatStatus(seatID string) domain.SeatStatus {
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seatID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	return seat.StatusCode
+}
+
+func (suite *SeatExpiryTestSuite) TestRunOnceReleasesExpiredOfferedSeats() {
+	suite.offerSeats(suite.seats[0:2], suite.now.Add(-time.Hour))
+	worker := suite.service.NewSeatExpiryWorker(SeatExpiryConfig{OfferExpiry: 30 * time.Minute}, func() time.Time { return suite.now })
+	report, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(2, report.ReleasedPerSeatingPlan[suite.spl.ID])
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[0].ID))
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[1].ID))
+}
+
+func (suite *SeatExpiryTestSuite) TestRunOnceUsesOrgOfferExpiry() {
+	suite.offerSeats(suite.seats[2:4], suite.now.Add(-time.Hour))
+	cfg := SeatExpiryConfig{
+		OfferExpiry:    30 * time.Minute,
+		OrgOfferExpiry: map[string]time.Duration{suite.org.ID: 2 * time.Hour},
+	}
+	worker := suite.service.NewSeatExpiryWorker(cfg, func() time.Time { return suite.now })
+	report, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(0, report.ReleasedPerSeatingPlan[suite.spl.ID])
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[2].ID))
+
+	// two hours later the offer of the org expires as well
+	worker = suite.service.NewSeatExpiryWorker(cfg, func() time.Time { return suite.now.Add(2 * time.Hour) })
+	report, err = worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(2, report.ReleasedPerSeatingPlan[suite.spl.ID])
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[2].ID))
+}
+
+func (suite *SeatExpiryTestSuite) TestRunOnceReleasesExpiredSeatHolds() {
+	seatIDs := []string{suite.seats[4].ID, suite.seats[5].ID}
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), seatIDs, time.Minute, suite.now.Add(-10*time.Minute))
+	suite.NoError(err)
+	worker := suite.service.NewSeatExpiryWorker(SeatExpiryConfig{OfferExpiry: time.Hour}, func() time.Time { return suite.now })
+	report, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(2, report.ReleasedPerSeatingPlan[suite.spl.ID])
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[4].ID))
+	_, err = suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now)
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+}
+
+func (suite *SeatExpiryTestSuite) TestRunOnceKeepsActivelyHeldSeats() {
+	seatIDs := []string{suite.seats[6].ID, suite.seats[7].ID}
+	_, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), seatIDs, 2*time.Hour, suite.now.Add(-time.Hour))
+	suite.NoError(err)
+	worker := suite.service.NewSeatExpiryWorker(SeatExpiryConfig{OfferExpiry: 30 * time.Minute}, func() time.Time { return suite.now })
+	_, err = worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[6].ID))
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[7].ID))
+}
+
+func (suite *SeatExpiryTestSuite) TestNewSeatExpiryWorkerDefaultsInterval() {
+	worker := suite.service.NewSeatExpiryWorker(SeatExpiryConfig{OfferExpiry: time.Hour}, nil)
+	suite.Equal(DefaultSeatExpiryInterval, worker.cfg.Interval)
+}
+
+func (suite *SeatExpiryTestSuite) TestRunOnceWithZeroConfig() {
+	suite.offerSeats(suite.seats[8:10], suite.now.Add(-time.Minute))
+	worker := suite.service.NewSeatExpiryWorker(SeatExpiryConfig{}, func() time.Time { return suite.now })
+	suite.Equal(DefaultSeatOfferExpiry, worker.cfg.OfferExpiry)
+	_, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[8].ID))
+
+	// offered seats of the org overridden with zero expiry are never released
+	later := func() time.Time { return suite.now.Add(time.Hour) }
+	worker = suite.service.NewSeatExpiryWorker(SeatExpiryConfig{OrgOfferExpiry: map[string]time.Duration{suite.org.ID: 0}}, later)
+	_, err = worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[8].ID))
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[9].ID))
+
+	worker = suite.service.NewSeatExpiryWorker(SeatExpiryConfig{}, later)
+	report, err := worker.RunOnce(suite.ctx)
+	suite.NoError(err)
+	suite.GreaterOrEqual(report.ReleasedPerSeatingPlan[suite.spl.ID], 2)
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[8].ID))
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[9].ID))
+}
+
+func (suite *SeatExpiryTestSuite) TestRunStopsWhenContextIsDone() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	runs := 0
+	cfg := SeatExpiryConfig{
+		Interval:    time.Millisecond,
+		OfferExpiry: time.Hour,
+		OnReport: func(domain.SeatExpiryReport) {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+		},
+	}
+	worker := suite.service.NewSeatExpiryWorker(cfg, func() time.Time { return suite.now })
+	err := worker.Run(ctx)
+	suite.ErrorIs(err, context.Canceled)
+	suite.Equal(3, runs)
+}
+
+func TestSeatExpiryTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatExpiryTestSuite))
+}
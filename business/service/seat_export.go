@@ -0,0 +1,217 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/proj/business/domain"
+)
+
+// seatExportRow is an exported seat
+type seatExportRow struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Num             int32   `json:"num"`
+	OverrideNum     *string `json:"override_num"`
+	RowName         string  `json:"row_name"`
+	RowNum          string  `json:"row_num"`
+	BlockName       *string `json:"block_name"`
+	BlockNum        *string `json:"block_num"`
+	GateName        *string `json:"gate_name"`
+	GateNum         *string `json:"gate_num"`
+	X               int32   `json:"x"`
+	Y               int32   `json:"y"`
+	PriceCategoryID *string `json:"price_category_id"`
+	StatusCode      int32   `json:"status_code"`
+	OrderID         string  `json:"order_id"`
+	Wheelchair      bool    `json:"wheelchair"`
+	Description     string  `json:"description"`
+}
+
+var seatExportCSVHeader = []string{
+	"id", "name", "num", "override_num", "row_name", "row_num", "block_name", "block_num", "gate_name", "gate_num",
+	"x", "y", "price_category_id", "status_code", "order_id", "wheelchair", "description",
+}
+
+func (r *seatExportRow) csvRecord() []string {
+	return []string{
+		r.ID, r.Name, strconv.Itoa(int(r.Num)), exportString(r.OverrideNum), r.RowName, r.RowNum,
+		exportString(r.BlockName), exportString(r.BlockNum), exportString(r.GateName), exportString(r.GateNum),
+		strconv.Itoa(int(r.X)), strconv.Itoa(int(r.Y)), exportString(r.PriceCategoryID), strconv.Itoa(int(r.StatusCode)),
+		r.OrderID, strconv.FormatBool(r.Wheelchair), r.Description,
+	}
+}
+
+func exportString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// seatExportWriter writes exported seats in a file format
+type seatExportWriter interface {
+	write(row *seatExportRow) error
+	// flush writes buffered seats, it's called after every page of seats
+	flush() error
+	close() error
+}
+
+type seatExportCSVWriter struct {
+	w *csv.Writer
+}
+
+func newSeatExportCSVWriter(w io.Writer) (*seatExportCSVWriter, error) {
+	cw := &seatExportCSVWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(seatExportCSVHeader); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *seatExportCSVWriter) write(row *seatExportRow) error {
+	return cw.w.Write(row.csvRecord())
+}
+
+func (cw *seatExportCSVWriter) flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *seatExportCSVWriter) close() error {
+	return cw.flush()
+}
+
+// seatExportJSONWriter writes a JSON array of seats element by element
+type seatExportJSONWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func newSeatExportJSONWriter(w io.Writer) (*seatExportJSONWriter, error) {
+	jw := &seatExportJSONWriter{w: bufio.NewWriter(w)}
+	if _, err := jw.w.WriteString("["); err != nil {
+		return nil, err
+	}
+	return jw, nil
+}
+
+func (jw *seatExportJSONWriter) write(row *seatExportRow) error {
+	b, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if jw.count > 0 {
+		if _, err := jw.w.WriteString(","); err != nil {
+			return err
+		}
+	}
+	jw.count++
+	_, err = jw.w.Write(b)
+	return err
+}
+
+func (jw *seatExportJSONWriter) flush() error {
+	return jw.w.Flush()
+}
+
+func (jw *seatExportJSONWriter) close() error {
+	if _, err := jw.w.WriteString("]\n"); err != nil {
+		return err
+	}
+	return jw.flush()
+}
+
+func newSeatExportWriter(w io.Writer, format domain.SeatExportFormat) (seatExportWriter, error) {
+	switch format {
+	case domain.SeatExportFormatCSV:
+		return newSeatExportCSVWriter(w)
+	case domain.SeatExportFormatJSON:
+		return newSeatExportJSONWriter(w)
+	}
+	return nil, fmt.Errorf("unknown seat export format %q", format)
+}
+
+// ExportSeats writes not deleted seats of the seating plan ids.SplID with their row, block and gate names and numbers
+// ordered by row and seat number. Seats are read and written page by page, so that big seating plans are never
+// loaded into memory at once.
+func (s *Service) ExportSeats(ctx context.Context, ids *domain.IDs, format domain.SeatExportFormat, w io.Writer) error {
+	ew, err := newSeatExportWriter(w, format)
+	if err != nil {
+		return err
+	}
+	query := &domain.SeatsQuery{
+		SeatsFilter: domain.SeatsFilter{OrgID: &ids.OrgID, SeatingPlanID: &ids.SplID},
+		Sort:        []domain.SeatSort{{Key: domain.SeatSortRow}, {Key: domain.SeatSortNum}},
+	}
+	page := domain.SeatsPageRequest{Limit: seatsPageSize}
+	for {
+		res, err := s.storage.GetSeatsPage(ctx, query, page)
+		if err != nil {
+			return fmt.Errorf("error while getting seats for export %w", err)
+		}
+		if err := s.exportSeatsPage(ctx, ids, res.Seats, ew); err != nil {
+			return err
+		}
+		if res.NextCursor == nil {
+			break
+		}
+		page.Cursor = res.NextCursor
+	}
+	if err := ew.close(); err != nil {
+		return fmt.Errorf("error while writing exported seats %w", err)
+	}
+	return nil
+}
+
+func (s *Service) exportSeatsPage(ctx context.Context, ids *domain.IDs, seats []domain.Seat, ew seatExportWriter) error {
+	if len(seats) == 0 {
+		return nil
+	}
+	seatIDs := make([]string, len(seats))
+	for i, seat := range seats {
+		seatIDs[i] = seat.ID
+	}
+	seatsWithSeatGroups, err := s.storage.GetSeatsWithSeatGroups(ctx, ids, seatIDs)
+	if err != nil {
+		return fmt.Errorf("error while getting seat groups for export %w", err)
+	}
+	seatGroupsBySeatID := make(map[string]domain.SeatWithSeatGroup, len(seatsWithSeatGroups))
+	for _, sg := range seatsWithSeatGroups {
+		seatGroupsBySeatID[sg.ID] = sg
+	}
+	for _, seat := range seats {
+		sg := seatGroupsBySeatID[seat.ID]
+		row := &seatExportRow{
+			ID:              seat.ID,
+			Name:            seat.Name,
+			Num:             seat.Num,
+			OverrideNum:     seat.OverrideNum,
+			RowName:         sg.RowName,
+			RowNum:          sg.RowNum,
+			BlockName:       sg.BlockName,
+			BlockNum:        sg.BlockNum,
+			GateName:        sg.GateName,
+			GateNum:         sg.GateNum,
+			X:               seat.X,
+			Y:               seat.Y,
+			PriceCategoryID: seat.PriceCategoryID,
+			StatusCode:      int32(seat.StatusCode),
+			OrderID:         seat.OrderID,
+			Wheelchair:      seat.Wheelchair,
+			Description:     seat.Description,
+		}
+		if err := ew.write(row); err != nil {
+			return fmt.Errorf("error while writing exported seats %w", err)
+		}
+	}
+	if err := ew.flush(); err != nil {
+		return fmt.Errorf("error while writing exported seats %w", err)
+	}
+	return nil
+}
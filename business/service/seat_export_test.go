@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SeatExportTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatExportTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatExportTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatExportTestSuite) TestExportSeatsCSV() {
+	var buf bytes.Buffer
+	err := suite.service.ExportSeats(suite.ctx, suite.ids(), domain.SeatExportFormatCSV, &buf)
+	suite.NoError(err)
+	records, err := csv.NewReader(&buf).ReadAll()
+	suite.NoError(err)
+	suite.Len(records, len(suite.seats)+1)
+	suite.Equal(seatExportCSVHeader, records[0])
+	for _, record := range records[1:] {
+		// row name
+		suite.NotEmpty(record[4])
+	}
+}
+
+func (suite *SeatExportTestSuite) TestExportSeatsJSON() {
+	var buf bytes.Buffer
+	err := suite.service.ExportSeats(suite.ctx, suite.ids(), domain.SeatExportFormatJSON, &buf)
+	suite.NoError(err)
+	var rows []seatExportRow
+	suite.NoError(json.Unmarshal(buf.Bytes(), &rows))
+	suite.Len(rows, len(suite.seats))
+	seatIDs := make([]string, len(rows))
+	for i, row := range rows {
+		seatIDs[i] = row.ID
+		suite.NotEmpty(row.RowName)
+		suite.Equal(int32(domain.SeatStatusAvailable), row.StatusCode)
+	}
+	suite.ElementsMatch(pageSeatIDs(suite.seats), seatIDs)
+}
+
+func (suite *SeatExportTestSuite) TestExportSeatsUnknownFormat() {
+	var buf bytes.Buffer
+	err := suite.service.ExportSeats(suite.ctx, suite.ids(), "xml", &buf)
+	suite.Error(err)
+	suite.Zero(buf.Len())
+}
+
+func TestSeatExportTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatExportTestSuite))
+}
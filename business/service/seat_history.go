@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/proj/business/domain"
+	"github.com/proj/business/storage"
+)
+
+const seatLogsReplayPageSize = 1000
+
+// GetSeatStateAt reconstructs the state of the seat ids.ID at the given time by replaying its seat logs
+func (s *Service) GetSeatStateAt(ctx context.Context, ids *domain.IDs, at time.Time) (*domain.SeatState, error) {
+	states, err := replaySeatLogs(ctx, s.storage, domain.SeatLogsFilter{OrgID: ids.OrgID, SeatID: &ids.ID, CreatedTo: &at})
+	if err != nil {
+		return nil, err
+	}
+	state, ok := states[ids.ID]
+	if !ok {
+		return nil, domain.ErrSeatNotFound
+	}
+	return state, nil
+}
+
+// GetSeatingPlanStateAt reconstructs the seats of the seating plan ids.SplID at the given time by replaying seat logs,
+// seats deleted at that time are left out
+func (s *Service) GetSeatingPlanStateAt(ctx context.Context, ids *domain.IDs, at time.Time) ([]*domain.SeatState, error) {
+	states, err := replaySeatLogs(ctx, s.storage, domain.SeatLogsFilter{OrgID: ids.OrgID, SeatingPlanID: &ids.SplID, CreatedTo: &at})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*domain.SeatState, 0, len(states))
+	for _, state := range states {
+		if state.DeletedAt == nil {
+			res = append(res, state)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		if res[i].Num != res[j].Num {
+			return res[i].Num < res[j].Num
+		}
+		return res[i].SeatID < res[j].SeatID
+	})
+	return res, nil
+}
+
+// replaySeatLogs applies the changes of all seat logs matching the filter in creation order and returns states by seat id
+func replaySeatLogs(ctx context.Context, s *storage.Storage, filter domain.SeatLogsFilter) (map[string]*domain.SeatState, error) {
+	states := make(map[string]*domain.SeatState)
+	for {
+		page, err := s.ListSeatLogs(ctx, &filter, seatLogsReplayPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("error while querying seat logs %w", err)
+		}
+		for _, log := range page.Logs {
+			state, ok := states[log.SeatID]
+			if !ok {
+				state = &domain.SeatState{SeatID: log.SeatID, SeatingPlanID: log.SeatingPlanID}
+				states[log.SeatID] = state
+			}
+			if err := state.Apply(log.Changes); err != nil {
+				return nil, fmt.Errorf("error while replaying seat log %v %w", log.ID, err)
+			}
+			state.ChangedAt = log.CreatedAt
+		}
+		if page.NextCursor == nil {
+			return states, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatHistoryTestSuite struct {
+	CommonSuite
+	seatIDs []string
+	// createdAt, updatedAt and deletedAt are the times of the seats history events
+	createdAt time.Time
+	updatedAt time.Time
+	deletedAt time.Time
+}
+
+func (suite *SeatHistoryTestSuite) SetupSuite() {
+	suite.SetupOrgWithSeats(12, 6)
+
+	suite.createdAt = time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	suite.updatedAt = suite.createdAt.Add(10 * time.Minute)
+	suite.deletedAt = suite.createdAt.Add(20 * time.Minute)
+	ids := &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID, SplID: suite.spl.ID}
+	name := "history"
+	newSeats := make([]*domain.NewSeat, 2)
+	for i := range newSeats {
+		newSeats[i] = &domain.NewSeat{ID: idgen.New("st"), SeatingPlanID: suite.spl.ID, Name: &name, Num: int32(i + 1), X: 10, Y: 20}
+		suite.seatIDs = append(suite.seatIDs, newSeats[i].ID)
+	}
+	err := suite.service.storage.CreateSeats(suite.ctx, ids, newSeats, suite.createdAt)
+	suite.NoError(err)
+	num := int32(7)
+	x := int32(15)
+	err = suite.service.storage.UpdateSeats(suite.ctx, ids, []*domain.UpdateSeat{{ID: suite.seatIDs[0], Num: &num, X: &x}}, suite.updatedAt)
+	suite.NoError(err)
+	err = suite.service.storage.DeleteSeats(suite.ctx, ids, []string{suite.seatIDs[1]}, suite.deletedAt)
+	suite.NoError(err)
+}
+
+func (suite *SeatHistoryTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatHistoryTestSuite) TestGetSeatStateAtBeforeCreation() {
+	_, err := suite.service.GetSeatStateAt(suite.ctx, &domain.IDs{OrgID: suite.org.ID, ID: suite.seatIDs[0]}, suite.createdAt.Add(-time.Minute))
+	suite.ErrorIs(err, domain.ErrSeatNotFound)
+}
+
+func (suite *SeatHistoryTestSuite) TestGetSeatStateAtAfterCreation() {
+	state, err := suite.service.GetSeatStateAt(suite.ctx, &domain.IDs{OrgID: suite.org.ID, ID: suite.seatIDs[0]}, suite.createdAt.Add(time.Minute))
+	suite.NoError(err)
+	suite.Equal(int32(1), state.Num)
+	suite.Equal(int32(10), state.X)
+	suite.Equal(int32(20), state.Y)
+	suite.Equal("history", state.Name)
+	suite.Equal(domain.SeatStatusAvailable, state.StatusCode)
+}
+
+func (suite *SeatHistoryTestSuite) TestGetSeatStateAtAfterUpdate() {
+	state, err := suite.service.GetSeatStateAt(suite.ctx, &domain.IDs{OrgID: suite.org.ID, ID: suite.seatIDs[0]}, suite.updatedAt.Add(time.Minute))
+	suite.NoError(err)
+	suite.Equal(int32(7), state.Num)
+	suite.Equal(int32(15), state.X)
+	suite.Equal(int32(20), state.Y)
+	suite.True(state.ChangedAt.Equal(suite.updatedAt))
+}
+
+func (suite *SeatHistoryTestSuite) TestGetSeatingPlanStateAt() {
+	ids := &domain.IDs{OrgID: suite.org.ID, SplID: suite.spl.ID}
+	states, err := suite.service.GetSeatingPlanStateAt(suite.ctx, ids, suite.updatedAt.Add(time.Minute))
+	suite.NoError(err)
+	suite.ElementsMatch(suite.seatIDs, historySeatIDs(states))
+
+	// the second seat is deleted
+	states, err = suite.service.GetSeatingPlanStateAt(suite.ctx, ids, suite.deletedAt.Add(time.Minute))
+	suite.NoError(err)
+	suite.ElementsMatch(suite.seatIDs[:1], historySeatIDs(states))
+}
+
+func (suite *SeatHistoryTestSuite) TestGetSeatStateAtReplaysLogsInWriteOrder() {
+	ids := &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID, SplID: suite.spl.ID}
+	changedAt := suite.deletedAt.Add(10 * time.Minute)
+	// all updates are logged in one transaction at the same time
+	nums := []int32{8, 3, 9, 4, 2}
+	updates := make([]*domain.UpdateSeat, len(nums))
+	for i := range nums {
+		updates[i] = &domain.UpdateSeat{ID: suite.seatIDs[0], Num: &nums[i]}
+	}
+	err := suite.service.storage.UpdateSeats(suite.ctx, ids, updates, changedAt)
+	suite.NoError(err)
+
+	state, err := suite.service.GetSeatStateAt(suite.ctx, &domain.IDs{OrgID: suite.org.ID, ID: suite.seatIDs[0]}, changedAt.Add(time.Minute))
+	suite.NoError(err)
+	suite.Equal(nums[len(nums)-1], state.Num)
+}
+
+// historySeatIDs returns ids of the seats created by the suite
+func historySeatIDs(states []*domain.SeatState) []string {
+	var seatIDs []string
+	for _, state := range states {
+		if state.Name == "history" {
+			seatIDs = append(seatIDs, state.SeatID)
+		}
+	}
+	return seatIDs
+}
+
+func TestSeatHistoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatHistoryTestSuite))
+}
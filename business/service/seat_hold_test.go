@@ -0,0 +1,156 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+)
+
+type SeatHoldTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+	now   time.Time
+}
+
+func (suite *SeatHoldTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+	suite.now = time.Now().UTC().Truncate(time.Second)
+}
+
+func (suite *SeatHoldTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatHoldTestSuite) seatStatus(seatID string) domain.SeatStatus {
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seatID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	return seat.StatusCode
+}
+
+func (suite *SeatHoldTestSuite) TestHoldSeats() {
+	seatIDs := []string{suite.seats[0].ID, suite.seats[1].ID}
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), seatIDs, time.Minute, suite.now)
+	suite.NoError(err)
+	suite.NotEmpty(hold.Token)
+	suite.ElementsMatch(seatIDs, hold.SeatIDs)
+	suite.Equal(suite.now.Add(time.Minute), hold.ExpiresAt)
+	for _, seatID := range seatIDs {
+		suite.Equal(domain.SeatStatusOffered, suite.seatStatus(seatID))
+	}
+
+	activeHold, err := suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now)
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, activeHold.SeatIDs)
+
+	// held seats can't be held again until the hold expires
+	_, err = suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{suite.seats[1].ID, suite.seats[2].ID}, time.Minute, suite.now)
+	var conflictErr *domain.SeatHoldConflictError
+	suite.ErrorAs(err, &conflictErr)
+	suite.Equal([]string{suite.seats[1].ID}, conflictErr.SeatIDs)
+	suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(suite.seats[2].ID))
+
+	_, err = suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now.Add(2*time.Minute))
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+	expiredHold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{suite.seats[1].ID}, time.Minute, suite.now.Add(2*time.Minute))
+	suite.NoError(err)
+	suite.NotEqual(hold.Token, expiredHold.Token)
+}
+
+func (suite *SeatHoldTestSuite) TestExtendSeatHold() {
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{suite.seats[3].ID}, time.Minute, suite.now)
+	suite.NoError(err)
+
+	extendedHold, err := suite.service.storage.ExtendSeatHold(suite.ctx, suite.ids(), hold.Token, 10*time.Minute, suite.now.Add(30*time.Second))
+	suite.NoError(err)
+	suite.WithinDuration(suite.now.Add(30*time.Second+10*time.Minute), extendedHold.ExpiresAt, time.Second)
+	_, err = suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now.Add(5*time.Minute))
+	suite.NoError(err)
+
+	// expired holds can't be extended
+	_, err = suite.service.storage.ExtendSeatHold(suite.ctx, suite.ids(), hold.Token, time.Minute, suite.now.Add(time.Hour))
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+}
+
+func (suite *SeatHoldTestSuite) TestReleaseSeatHold() {
+	seatIDs := []string{suite.seats[4].ID, suite.seats[5].ID}
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), seatIDs, time.Minute, suite.now)
+	suite.NoError(err)
+
+	err = suite.service.storage.ReleaseSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now)
+	suite.NoError(err)
+	for _, seatID := range seatIDs {
+		suite.Equal(domain.SeatStatusAvailable, suite.seatStatus(seatID))
+	}
+	_, err = suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now)
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+	err = suite.service.storage.ReleaseSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now)
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+}
+
+func (suite *SeatHoldTestSuite) TestConvertSeatHoldToOrder() {
+	seatIDs := []string{suite.seats[6].ID, suite.seats[7].ID}
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), seatIDs, time.Minute, suite.now)
+	suite.NoError(err)
+
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	soldSeatIDs, err := suite.service.storage.ConvertSeatHoldToOrder(suite.ctx, suite.ids(), hold.Token, orderID, orderItemID, suite.now)
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, soldSeatIDs)
+	for _, seatID := range seatIDs {
+		seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seatID, OrgID: suite.org.ID})
+		suite.NoError(err)
+		suite.Equal(domain.SeatStatusSold, seat.StatusCode)
+		suite.Equal(orderID, seat.OrderID)
+	}
+	_, err = suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, suite.now)
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+}
+
+func (suite *SeatHoldTestSuite) TestConvertExpiredSeatHoldToOrder() {
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{suite.seats[8].ID}, time.Minute, suite.now)
+	suite.NoError(err)
+
+	_, err = suite.service.storage.ConvertSeatHoldToOrder(suite.ctx, suite.ids(), hold.Token, idgen.New("ord"), idgen.New("oi"), suite.now.Add(time.Hour))
+	suite.ErrorIs(err, domain.ErrSeatHoldNotFound)
+	suite.Equal(domain.SeatStatusOffered, suite.seatStatus(suite.seats[8].ID))
+}
+
+func (suite *SeatHoldTestSuite) TestConcurrentHoldSeatsHoldEachSeatOnce() {
+	seatIDs := []string{suite.seats[9].ID, suite.seats[10].ID, suite.seats[11].ID}
+	const holders = 8
+	var wg sync.WaitGroup
+	holds := make([]*domain.SeatHold, holders)
+	errs := make([]error, holders)
+	for i := 0; i < holders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every holder overlaps with the others on the middle seat
+			holds[i], errs[i] = suite.service.storage.HoldSeats(suite.ctx, suite.ids(), seatIDs[i%2:i%2+2], time.Minute, suite.now)
+		}(i)
+	}
+	wg.Wait()
+
+	var tokens []string
+	for i := 0; i < holders; i++ {
+		if errs[i] != nil {
+			suite.ErrorIs(errs[i], domain.ErrSeatConflict)
+			continue
+		}
+		tokens = append(tokens, holds[i].Token)
+	}
+	if !suite.Len(tokens, 1) {
+		return
+	}
+	hold, err := suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), tokens[0], suite.now)
+	suite.NoError(err)
+	suite.Len(hold.SeatIDs, 2)
+	suite.Contains(hold.SeatIDs, suite.seats[10].ID)
+}
+
+func TestSeatHoldTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatHoldTestSuite))
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+)
+
+// columns of imported seats
+const (
+	seatImportName          = "name"
+	seatImportNum           = "num"
+	seatImportRow           = "row"
+	seatImportBlock         = "block"
+	seatImportGate          = "gate"
+	seatImportX             = "x"
+	seatImportY             = "y"
+	seatImportPriceCategory = "price_category"
+	seatImportWheelchair    = "wheelchair"
+	seatImportOverrideNum   = "override_num"
+	seatImportDescription   = "description"
+	seatImportSvgPath       = "svg_path"
+	seatImportSvgTransform  = "svg_transform"
+)
+
+var seatImportCSVColumns = map[string]bool{
+	seatImportName:          true,
+	seatImportNum:           true,
+	seatImportRow:           true,
+	seatImportBlock:         true,
+	seatImportGate:          true,
+	seatImportX:             true,
+	seatImportY:             true,
+	seatImportPriceCategory: true,
+	seatImportWheelchair:    true,
+	seatImportOverrideNum:   true,
+	seatImportDescription:   true,
+}
+
+var seatImportRequiredColumns = []string{seatImportNum, seatImportRow, seatImportX, seatImportY}
+
+// seatImportRecord is a seat read from an imported file, values are kept as read by column
+type seatImportRecord struct {
+	line   int
+	values map[string]string
+	// errs are errors found while reading the record
+	errs []domain.SeatImportLineError
+}
+
+// seatsPageSize is the number of seats read at once when all seats of a seating plan are needed
+const seatsPageSize = 1000
+
+// ImportSeatsCSV creates seats of the seating plan ids.SplID from the CSV. The first line names the columns,
+// row and block, gate and price category are matched by id or name. Nothing is created when any line is invalid
+// or when dryRun is set, the result lists errors of all lines then.
+func (s *Service) ImportSeatsCSV(ctx context.Context, ids *domain.IDs, r io.Reader, dryRun bool) (*domain.SeatImportResult, error) {
+	records, err := readSeatsCSV(r)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading seats csv %w", err)
+	}
+	return s.importSeats(ctx, ids, records, dryRun)
+}
+
+func readSeatsCSV(r io.Reader) ([]seatImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// lines with a wrong number of columns are reported as line errors instead of failing the whole file
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(header))
+	present := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !seatImportCSVColumns[column] {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		if present[column] {
+			return nil, fmt.Errorf("duplicate column %q", column)
+		}
+		columns[i] = column
+		present[column] = true
+	}
+	for _, column := range seatImportRequiredColumns {
+		if !present[column] {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	var records []seatImportRecord
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		record := seatImportRecord{line: line, values: make(map[string]string, len(fields))}
+		if len(fields) != len(columns) {
+			record.errs = append(record.errs, domain.SeatImportLineError{
+				Line:    line,
+				Message: fmt.Sprintf("expected %d columns, got %d", len(columns), len(fields)),
+			})
+			records = append(records, record)
+			continue
+		}
+		for i, field := range fields {
+			record.values[columns[i]] = strings.TrimSpace(field)
+		}
+		records = append(records, record)
+	}
+}
+
+// importSeats validates the records against the seating plan and creates the seats when all of them are valid
+func (s *Service) importSeats(ctx context.Context, ids *domain.IDs, records []seatImportRecord, dryRun bool) (*domain.SeatImportResult, error) {
+	refs, err := s.getSeatImportRefs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	res := &domain.SeatImportResult{DryRun: dryRun, Lines: len(records)}
+	newSeats := make([]*domain.NewSeat, 0, len(records))
+	for _, record := range records {
+		newSeat, lineErrs := refs.newSeat(record, ids.SplID)
+		res.Errors = append(res.Errors, lineErrs...)
+		if len(lineErrs) == 0 {
+			newSeats = append(newSeats, newSeat)
+		}
+	}
+	if dryRun || len(res.Errors) > 0 {
+		return res, nil
+	}
+	if err := s.storage.CreateSeats(ctx, ids, newSeats, time.Now()); err != nil {
+		return nil, fmt.Errorf("error while creating imported seats %w", err)
+	}
+	for _, newSeat := range newSeats {
+		res.SeatIDs = append(res.SeatIDs, newSeat.ID)
+	}
+	return res, nil
+}
+
+// seatImportRefs resolves references of imported seats and finds duplicate seats
+type seatImportRefs struct {
+	// seatGroups are seat groups of the seating plan by id and by name
+	seatGroups map[string][]domain.SeatGroup
+	// seatGroupColumns are the columns existing seats reference seat groups by, groups without seats match any column
+	seatGroupColumns map[string]map[string]bool
+	// rowBlocks are the blocks existing seats of a row belong to
+	rowBlocks       map[string]map[string]bool
+	priceCategories map[string][]domain.PriceCategory
+	// seatLines are lines of already imported seats by seatImportKey, existing seats have line 0
+	seatLines map[string]int
+}
+
+func (s *Service) getSeatImportRefs(ctx context.Context, ids *domain.IDs) (*seatImportRefs, error) {
+	refs := &seatImportRefs{
+		seatGroups:       make(map[string][]domain.SeatGroup),
+		seatGroupColumns: make(map[string]map[string]bool),
+		rowBlocks:        make(map[string]map[string]bool),
+		priceCategories:  make(map[string][]domain.PriceCategory),
+		seatLines:        make(map[string]int),
+	}
+	sgs, err := s.GetSeatGroups(ctx, &domain.SeatGroupsFilter{OrgID: &ids.OrgID, SeatingPlanID: &ids.SplID})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting seat groups %w", err)
+	}
+	for _, sg := range sgs {
+		refs.seatGroups[sg.ID] = append(refs.seatGroups[sg.ID], sg)
+		if sg.Name != sg.ID {
+			refs.seatGroups[sg.Name] = append(refs.seatGroups[sg.Name], sg)
+		}
+	}
+	pcs, err := s.GetPriceCategories(ctx, &domain.PriceCategoriesFilter{OrgID: &ids.OrgID, SeatingPlanID: &ids.SplID})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting price categories %w", err)
+	}
+	for _, pc := range pcs {
+		refs.priceCategories[pc.ID] = append(refs.priceCategories[pc.ID], pc)
+		if pc.Name != pc.ID {
+			refs.priceCategories[pc.Name] = append(refs.priceCategories[pc.Name], pc)
+		}
+	}
+	seats, err := s.getSeatingPlanSeats(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, seat := range seats {
+		if seat.SeatRowID != nil {
+			refs.seatLines[seatImportKey(*seat.SeatRowID, seat.Num)] = 0
+		}
+		refs.addSeatGroupUse(seat.SeatRowID, seatImportRow)
+		refs.addSeatGroupUse(seat.SeatBlockID, seatImportBlock)
+		refs.addSeatGroupUse(seat.SeatGateID, seatImportGate)
+		if seat.SeatRowID != nil && seat.SeatBlockID != nil {
+			addToSet(refs.rowBlocks, *seat.SeatRowID, *seat.SeatBlockID)
+		}
+	}
+	return refs, nil
+}
+
+// addSeatGroupUse records that a seat references the seat group by the column
+func (refs *seatImportRefs) addSeatGroupUse(seatGroupID *string, column string) {
+	if seatGroupID != nil {
+		addToSet(refs.seatGroupColumns, *seatGroupID, column)
+	}
+}
+
+func addToSet(sets map[string]map[string]bool, key, value string) {
+	if sets[key] == nil {
+		sets[key] = make(map[string]bool)
+	}
+	sets[key][value] = true
+}
+
+// findSeatGroups returns seat groups the value may reference in the column. Groups which existing seats reference
+// by another column are left out, a row is looked up inside the block when blockID is set.
+func (refs *seatImportRefs) findSeatGroups(value, column string, blockID *string) []domain.SeatGroup {
+	var sgs []domain.SeatGroup
+	for _, sg := range refs.seatGroups[value] {
+		if columns := refs.seatGroupColumns[sg.ID]; len(columns) > 0 && !columns[column] {
+			continue
+		}
+		if blocks := refs.rowBlocks[sg.ID]; column == seatImportRow && blockID != nil && len(blocks) > 0 && !blocks[*blockID] {
+			continue
+		}
+		sgs = append(sgs, sg)
+	}
+	return sgs
+}
+
+// getSeatingPlanSeats returns all not deleted seats of the seating plan ids.SplID
+func (s *Service) getSeatingPlanSeats(ctx context.Context, ids *domain.IDs) ([]domain.Seat, error) {
+	query := &domain.SeatsQuery{SeatsFilter: domain.SeatsFilter{OrgID: &ids.OrgID, SeatingPlanID: &ids.SplID}}
+	page := domain.SeatsPageRequest{Limit: seatsPageSize}
+	var seats []domain.Seat
+	for {
+		res, err := s.storage.GetSeatsPage(ctx, query, page)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting seating plan seats %w", err)
+		}
+		seats = append(seats, res.Seats...)
+		if res.NextCursor == nil {
+			return seats, nil
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
+// seatImportKey identifies a seat by its row and number
+func seatImportKey(seatRowID string, num int32) string {
+	return seatRowID + "/" + strconv.Itoa(int(num))
+}
+
+// newSeat validates the record and returns the seat to create
+func (refs *seatImportRefs) newSeat(record seatImportRecord, seatingPlanID string) (*domain.NewSeat, []domain.SeatImportLineError) {
+	// values of records which couldn't be read aren't validated
+	if len(record.errs) > 0 {
+		return nil, record.errs
+	}
+	var lineErrs []domain.SeatImportLineError
+	fail := func(column, format string, args ...interface{}) {
+		lineErrs = append(lineErrs, domain.SeatImportLineError{Line: record.line, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+	value := func(column string) (string, bool) {
+		v := record.values[column]
+		return v, v != ""
+	}
+	integer := func(column string) int32 {
+		v, ok := value(column)
+		if !ok {
+			fail(column, "value is required")
+			return 0
+		}
+		i, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			fail(column, "%q is not an integer", v)
+		}
+		return int32(i)
+	}
+	seatGroupID := func(column string, blockID *string) *string {
+		v, ok := value(column)
+		if !ok {
+			return nil
+		}
+		switch sgs := refs.findSeatGroups(v, column, blockID); len(sgs) {
+		case 0:
+			fail(column, "unknown %s %q", column, v)
+		case 1:
+			return &sgs[0].ID
+		default:
+			fail(column, "ambiguous %s %q", column, v)
+		}
+		return nil
+	}
+	optional := func(column string) *string {
+		if v, ok := value(column); ok {
+			return &v
+		}
+		return nil
+	}
+
+	newSeat := &domain.NewSeat{
+		ID:            idgen.New("st"),
+		SeatingPlanID: seatingPlanID,
+		Num:           integer(seatImportNum),
+		X:             integer(seatImportX),
+		Y:             integer(seatImportY),
+		Name:          optional(seatImportName),
+		SeatBlockID:   seatGroupID(seatImportBlock, nil),
+		SeatGateID:    seatGroupID(seatImportGate, nil),
+		OverrideNum:   optional(seatImportOverrideNum),
+		Description:   optional(seatImportDescription),
+		SvgPath:       optional(seatImportSvgPath),
+		SvgTransform:  optional(seatImportSvgTransform),
+	}
+	if _, ok := value(seatImportRow); ok {
+		// equally named rows of different blocks are told apart by the block of the line
+		newSeat.SeatRowID = seatGroupID(seatImportRow, newSeat.SeatBlockID)
+	} else {
+		fail(seatImportRow, "value is required")
+	}
+	if v, ok := value(seatImportPriceCategory); ok {
+		switch pcs := refs.priceCategories[v]; len(pcs) {
+		case 0:
+			fail(seatImportPriceCategory, "unknown price category %q", v)
+		case 1:
+			newSeat.PriceCategoryID = &pcs[0].ID
+		default:
+			fail(seatImportPriceCategory, "ambiguous price category %q", v)
+		}
+	}
+	if v, ok := value(seatImportWheelchair); ok {
+		wheelchair, err := strconv.ParseBool(v)
+		if err != nil {
+			fail(seatImportWheelchair, "%q is not a boolean", v)
+		}
+		newSeat.Wheelchair = &wheelchair
+	}
+	if len(lineErrs) > 0 {
+		return nil, lineErrs
+	}
+
+	key := seatImportKey(*newSeat.SeatRowID, newSeat.Num)
+	if line, ok := refs.seatLines[key]; ok {
+		if line == 0 {
+			fail("", "seat %d already exists in row %q", newSeat.Num, record.values[seatImportRow])
+		} else {
+			fail("", "duplicate of line %d", line)
+		}
+		return nil, lineErrs
+	}
+	refs.seatLines[key] = record.line
+	return newSeat, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type SeatImportTestSuite struct {
+	CommonSuite
+	seats     []domain.Seat
+	seatGroup domain.SeatGroup
+}
+
+func (suite *SeatImportTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+	sgs, err := suite.service.GetSeatGroups(suite.ctx, &domain.SeatGroupsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID})
+	suite.NoError(err)
+	for _, sg := range sgs {
+		if sg.ID == *suite.seats[0].SeatRowID {
+			suite.seatGroup = sg
+		}
+	}
+}
+
+func (suite *SeatImportTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatImportTestSuite) TestImportSeatsCSVDryRunReportsLineErrors() {
+	csv := strings.Join([]string{
+		"name,num,row,x,y,price_category,wheelchair",
+		fmt.Sprintf("A101,101,%s,10,20,%s,false", suite.seatGroup.Name, *suite.seats[0].PriceCategoryID),
+		fmt.Sprintf("A101 again,101,%s,10,30,,", suite.seatGroup.ID),
+		fmt.Sprintf("A102,102,%s,20,20,unknown,", suite.seatGroup.Name),
+		"A103,103,,30,20,,",
+		"A104,104,unknown row,x,20,,maybe",
+	}, "\n")
+	res, err := suite.service.ImportSeatsCSV(suite.ctx, suite.ids(), strings.NewReader(csv), true)
+	suite.NoError(err)
+	suite.True(res.DryRun)
+	suite.Equal(5, res.Lines)
+	suite.Empty(res.SeatIDs)
+	suite.Equal([]domain.SeatImportLineError{
+		{Line: 3, Message: "duplicate of line 2"},
+		{Line: 4, Column: "price_category", Message: `unknown price category "unknown"`},
+		{Line: 5, Column: "row", Message: "value is required"},
+		{Line: 6, Column: "x", Message: `"x" is not an integer`},
+		{Line: 6, Column: "row", Message: `unknown row "unknown row"`},
+		{Line: 6, Column: "wheelchair", Message: `"maybe" is not a boolean`},
+	}, res.Errors)
+}
+
+func (suite *SeatImportTestSuite) TestImportSeatsCSVReportsWrongColumnCount() {
+	csv := strings.Join([]string{
+		"num,row,x,y",
+		fmt.Sprintf("301,%s,10,60", suite.seatGroup.ID),
+		fmt.Sprintf("302,%s,20", suite.seatGroup.ID),
+		fmt.Sprintf("303,%s,30,60,extra", suite.seatGroup.ID),
+	}, "\n")
+	res, err := suite.service.ImportSeatsCSV(suite.ctx, suite.ids(), strings.NewReader(csv), true)
+	suite.NoError(err)
+	suite.Equal(3, res.Lines)
+	suite.Equal([]domain.SeatImportLineError{
+		{Line: 3, Message: "expected 4 columns, got 3"},
+		{Line: 4, Message: "expected 4 columns, got 5"},
+	}, res.Errors)
+}
+
+func (suite *SeatImportTestSuite) TestImportSeatsCSVRejectsExistingSeats() {
+	csv := fmt.Sprintf("num,row,x,y\n%d,%s,0,0\n", suite.seats[0].Num, *suite.seats[0].SeatRowID)
+	res, err := suite.service.ImportSeatsCSV(suite.ctx, suite.ids(), strings.NewReader(csv), false)
+	suite.NoError(err)
+	suite.Len(res.Errors, 1)
+	suite.Empty(res.SeatIDs)
+}
+
+func (suite *SeatImportTestSuite) TestImportSeatsCSVCreatesSeats() {
+	csv := fmt.Sprintf("num,row,x,y,name,wheelchair\n201,%[1]s,10,50,B201,true\n202,%[1]s,20,50,B202,\n", suite.seatGroup.ID)
+	res, err := suite.service.ImportSeatsCSV(suite.ctx, suite.ids(), strings.NewReader(csv), false)
+	suite.NoError(err)
+	suite.Empty(res.Errors)
+	suite.Len(res.SeatIDs, 2)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: res.SeatIDs[0], OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal("B201", seat.Name)
+	suite.Equal(int32(201), seat.Num)
+	suite.Equal(suite.seatGroup.ID, *seat.SeatRowID)
+	suite.True(seat.Wheelchair)
+}
+
+func (suite *SeatImportTestSuite) TestImportSeatsCSVRejectsInvalidHeader() {
+	for _, csv := range []string{"", "num,row,x", "num,row,x,y,color", "num,row,x,y,num"} {
+		_, err := suite.service.ImportSeatsCSV(suite.ctx, suite.ids(), strings.NewReader(csv), true)
+		suite.Error(err, csv)
+	}
+}
+
+func (suite *SeatImportTestSuite) TestSeatImportRefsResolveSeatGroupsByColumnAndBlock() {
+	rowA := domain.SeatGroup{ID: "sg-row-a", Name: "1"}
+	rowB := domain.SeatGroup{ID: "sg-row-b", Name: "1"}
+	blockA := domain.SeatGroup{ID: "sg-block-a", Name: "A"}
+	blockB := domain.SeatGroup{ID: "sg-block-b", Name: "B"}
+	gate := domain.SeatGroup{ID: "sg-gate", Name: "A"}
+	refs := &seatImportRefs{
+		seatGroups:       make(map[string][]domain.SeatGroup),
+		seatGroupColumns: make(map[string]map[string]bool),
+		rowBlocks:        make(map[string]map[string]bool),
+		seatLines:        make(map[string]int),
+	}
+	for _, sg := range []domain.SeatGroup{rowA, rowB, blockA, blockB, gate} {
+		refs.seatGroups[sg.Name] = append(refs.seatGroups[sg.Name], sg)
+	}
+	for _, seat := range []domain.Seat{
+		{SeatRowID: &rowA.ID, SeatBlockID: &blockA.ID, SeatGateID: &gate.ID},
+		{SeatRowID: &rowB.ID, SeatBlockID: &blockB.ID},
+	} {
+		refs.addSeatGroupUse(seat.SeatRowID, seatImportRow)
+		refs.addSeatGroupUse(seat.SeatBlockID, seatImportBlock)
+		refs.addSeatGroupUse(seat.SeatGateID, seatImportGate)
+		addToSet(refs.rowBlocks, *seat.SeatRowID, *seat.SeatBlockID)
+	}
+
+	record := func(line int, values map[string]string) seatImportRecord {
+		values[seatImportNum], values[seatImportX], values[seatImportY] = strconv.Itoa(line), "0", "0"
+		return seatImportRecord{line: line, values: values}
+	}
+	// the block and the gate named "A" are told apart by their column, row "1" by the block
+	newSeat, errs := refs.newSeat(record(2, map[string]string{seatImportRow: "1", seatImportBlock: "B", seatImportGate: "A"}), suite.spl.ID)
+	suite.Empty(errs)
+	suite.Equal(rowB.ID, *newSeat.SeatRowID)
+	suite.Equal(blockB.ID, *newSeat.SeatBlockID)
+	suite.Equal(gate.ID, *newSeat.SeatGateID)
+
+	newSeat, errs = refs.newSeat(record(3, map[string]string{seatImportRow: "1", seatImportBlock: "A"}), suite.spl.ID)
+	suite.Empty(errs)
+	suite.Equal(rowA.ID, *newSeat.SeatRowID)
+
+	// without a block the row stays ambiguous
+	_, errs = refs.newSeat(record(4, map[string]string{seatImportRow: "1"}), suite.spl.ID)
+	suite.Equal([]domain.SeatImportLineError{{Line: 4, Column: seatImportRow, Message: `ambiguous row "1"`}}, errs)
+}
+
+func TestSeatImportTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatImportTestSuite))
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatLinkedTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatLinkedTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+
+	// groups of seats 0 and 1, 2 and 3 and of 6, 7 and 8 linked to 7
+	links := map[int]int{0: 1, 2: 3, 6: 7, 8: 7}
+	updates := make([]*domain.UpdateSeat, 0, len(links))
+	for i, j := range links {
+		linkedSeatID := suite.seats[j].ID
+		updates = append(updates, &domain.UpdateSeat{ID: suite.seats[i].ID, LinkedSeatID: &linkedSeatID})
+	}
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), updates, time.Now())
+	suite.NoError(err)
+}
+
+func (suite *SeatLinkedTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatLinkedTestSuite) TestLinkingLocksSeat() {
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[6].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(suite.seats[7].ID, seat.LinkedSeatID)
+	suite.Equal(domain.SeatStatusLocked, seat.StatusCode)
+}
+
+func (suite *SeatLinkedTestSuite) TestUnlinkingFreesSeat() {
+	linkedSeatID := suite.seats[11].ID
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: suite.seats[10].ID, LinkedSeatID: &linkedSeatID}}, time.Now())
+	suite.NoError(err)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[10].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusLocked, seat.StatusCode)
+
+	unlinkedSeatID := ""
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: suite.seats[10].ID, LinkedSeatID: &unlinkedSeatID}}, time.Now())
+	suite.NoError(err)
+	seat, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[10].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Empty(seat.LinkedSeatID)
+	suite.Equal(domain.SeatStatusAvailable, seat.StatusCode)
+}
+
+func (suite *SeatLinkedTestSuite) TestGetLinkedSeatGroups() {
+	groups, err := suite.service.storage.GetLinkedSeatGroups(suite.ctx, suite.ids(), []string{suite.seats[8].ID, suite.seats[4].ID})
+	suite.NoError(err)
+	suite.Len(groups, 1)
+	suite.ElementsMatch([]string{suite.seats[6].ID, suite.seats[7].ID, suite.seats[8].ID}, groups[0].SeatIDs)
+}
+
+func (suite *SeatLinkedTestSuite) TestUpdateSeatsStatusChangesWholeGroup() {
+	change, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.seats[1].ID},
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	suite.ElementsMatch([]string{suite.seats[0].ID, suite.seats[1].ID}, change.SeatIDs)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[0].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusSold, seat.StatusCode)
+}
+
+func (suite *SeatLinkedTestSuite) TestHoldSeatsHoldsWholeGroup() {
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{suite.seats[2].ID}, time.Minute, time.Now())
+	suite.NoError(err)
+	suite.ElementsMatch([]string{suite.seats[2].ID, suite.seats[3].ID}, hold.SeatIDs)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[3].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusOffered, seat.StatusCode)
+
+	err = suite.service.storage.ReleaseSeatHold(suite.ctx, suite.ids(), hold.Token, time.Now())
+	suite.NoError(err)
+	seat, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[3].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusAvailable, seat.StatusCode)
+	// the linked seat is locked again
+	seat, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[2].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusLocked, seat.StatusCode)
+}
+
+func (suite *SeatLinkedTestSuite) TestValidateSeatRulesRejectsPartOfGroup() {
+	requested := map[string]map[string][]domain.Seat{
+		suite.spl.ID: {*suite.seats[6].SeatRowID: {suite.seats[6], suite.seats[7]}},
+	}
+	err := suite.service.ValidateSeatRules(suite.ctx, suite.org.ID, map[string]string{}, requested)
+	var linkedErr *domain.SeatRuleLinkedSeatsError
+	suite.ErrorAs(err, &linkedErr)
+	suite.Equal([]string{suite.seats[8].ID}, linkedErr.MissingSeatIDs)
+}
+
+func TestSeatLinkedTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatLinkedTestSuite))
+}
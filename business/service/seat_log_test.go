@@ -0,0 +1,203 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatLogTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatLogTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatLogTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatLogTestSuite) lastSeatLog(seatID string) *domain.SeatLogEntry {
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{OrgID: suite.org.ID, SeatID: &seatID}, 100)
+	suite.NoError(err)
+	suite.NotEmpty(page.Logs)
+	return page.Logs[len(page.Logs)-1]
+}
+
+func (suite *SeatLogTestSuite) TestCreateSeatsLogsAllFields() {
+	seatID := idgen.New("st")
+	name, description := "new seat", "near the stage"
+	err := suite.service.storage.CreateSeats(suite.ctx, suite.ids(), []*domain.NewSeat{
+		{ID: seatID, SeatingPlanID: suite.spl.ID, Num: 100, X: 10, Y: 20, Name: &name, Description: &description},
+	}, time.Now())
+	suite.NoError(err)
+
+	log := suite.lastSeatLog(seatID)
+	suite.Equal(suite.spl.ID, log.SeatingPlanID)
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: float64(100)}, log.Changes[domain.SeatFieldNum])
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: name}, log.Changes[domain.SeatFieldName])
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: float64(10)}, log.Changes[domain.SeatFieldX])
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: description}, log.Changes[domain.SeatFieldDescription])
+	// fields which are not set are not logged
+	suite.NotContains(log.Changes, domain.SeatFieldLinkedSeatID)
+	suite.NotContains(log.Changes, domain.SeatFieldSeatGateID)
+}
+
+func (suite *SeatLogTestSuite) TestGetSeatLogsOfOrg() {
+	logs, err := suite.service.storage.GetSeatLogs(suite.ctx, suite.org.ID, 1000, 0)
+	suite.NoError(err)
+	suite.NotEmpty(logs)
+	// logs of other orgs are left out
+	for _, log := range logs {
+		suite.Equal(suite.spl.ID, log.SeatingPlanID, log.ID)
+	}
+	_, err = suite.service.storage.GetSeatLogs(suite.ctx, "", 1000, 0)
+	suite.ErrorIs(err, domain.ErrSeatLogsOrgRequired)
+}
+
+func (suite *SeatLogTestSuite) TestUpdateSeatsLogsChangedFields() {
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[0].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+
+	name := "renamed"
+	x := seat.X + 5
+	y := seat.Y
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: seat.ID, Name: &name, X: &x, Y: &y}}, time.Now())
+	suite.NoError(err)
+
+	log := suite.lastSeatLog(seat.ID)
+	suite.Equal("updated", log.Action)
+	suite.Equal(name, log.Changes[domain.SeatFieldName].New)
+	suite.Equal(domain.SeatFieldChange{Old: float64(seat.X), New: float64(x)}, log.Changes[domain.SeatFieldX])
+	// unchanged fields are left out even when they are set
+	suite.NotContains(log.Changes, domain.SeatFieldY)
+	suite.Len(log.Changes, 2)
+}
+
+func (suite *SeatLogTestSuite) TestUpdateSeatsLogsLinkAndStatus() {
+	linkedSeatID := suite.seats[2].ID
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: suite.seats[1].ID, LinkedSeatID: &linkedSeatID}}, time.Now())
+	suite.NoError(err)
+
+	log := suite.lastSeatLog(suite.seats[1].ID)
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: linkedSeatID}, log.Changes[domain.SeatFieldLinkedSeatID])
+	suite.Equal(float64(domain.SeatStatusLocked), log.Changes[domain.SeatFieldStatusCode].New)
+}
+
+func (suite *SeatLogTestSuite) TestUpdateSeatsStatusLogsOrder() {
+	seatID := suite.seats[3].ID
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:        []string{seatID},
+		StatusCode:     domain.SeatStatusSold,
+		AddOrderID:     &orderID,
+		AddOrderItemID: &orderItemID,
+		UpdatedAt:      time.Now(),
+	})
+	suite.NoError(err)
+
+	log := suite.lastSeatLog(seatID)
+	suite.Equal(orderID, log.OrderID)
+	suite.Equal(domain.SeatFieldChange{Old: float64(domain.SeatStatusAvailable), New: float64(domain.SeatStatusSold)}, log.Changes[domain.SeatFieldStatusCode])
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: orderID}, log.Changes[domain.SeatFieldOrderID])
+	suite.Equal(domain.SeatFieldChange{Old: nil, New: orderItemID}, log.Changes[domain.SeatFieldOrderItemID])
+
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:       []string{seatID},
+		StatusCode:    domain.SeatStatusAvailable,
+		RemoveOrderID: true,
+		UpdatedAt:     time.Now(),
+	})
+	suite.NoError(err)
+
+	log = suite.lastSeatLog(seatID)
+	suite.Equal(domain.SeatFieldChange{Old: float64(domain.SeatStatusSold), New: float64(domain.SeatStatusAvailable)}, log.Changes[domain.SeatFieldStatusCode])
+	suite.Equal(domain.SeatFieldChange{Old: orderID, New: nil}, log.Changes[domain.SeatFieldOrderID])
+	suite.Equal(domain.SeatFieldChange{Old: orderItemID, New: nil}, log.Changes[domain.SeatFieldOrderItemID])
+}
+
+func (suite *SeatLogTestSuite) listSeatLogs(filter domain.SeatLogsFilter, limit int64) ([]string, *string) {
+	filter.OrgID = suite.org.ID
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &filter, limit)
+	suite.NoError(err)
+	seatIDs := make([]string, len(page.Logs))
+	for i, log := range page.Logs {
+		seatIDs[i] = log.SeatID
+	}
+	return seatIDs, page.NextCursor
+}
+
+func (suite *SeatLogTestSuite) TestListSeatLogsFilters() {
+	from := time.Date(2031, 1, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	seat4, seat5, seat6, seat7 := suite.seats[4].ID, suite.seats[5].ID, suite.seats[6].ID, suite.seats[7].ID
+	name := "filtered"
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: seat4, Name: &name}}, from)
+	suite.NoError(err)
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: seat5, Name: &name}}, from.Add(time.Minute))
+	suite.NoError(err)
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:        []string{seat6},
+		StatusCode:     domain.SeatStatusSold,
+		AddOrderID:     &orderID,
+		AddOrderItemID: &orderItemID,
+		UpdatedAt:      from.Add(2 * time.Minute),
+	})
+	suite.NoError(err)
+	// changed without user
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, &domain.IDs{OrgID: suite.org.ID}, domain.UpdateSeatStatus{
+		SeatIDs:    []string{seat7},
+		StatusCode: domain.SeatStatusOffered,
+		UpdatedAt:  from.Add(3 * time.Minute),
+	})
+	suite.NoError(err)
+
+	seatIDs, _ := suite.listSeatLogs(domain.SeatLogsFilter{CreatedFrom: &from, CreatedTo: &to}, 100)
+	suite.Equal([]string{seat4, seat5, seat6, seat7}, seatIDs)
+
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{SeatID: &seat5, CreatedFrom: &from, CreatedTo: &to}, 100)
+	suite.Equal([]string{seat5}, seatIDs)
+
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{SeatingPlanID: &suite.spl.ID, CreatedFrom: &from, CreatedTo: &to}, 100)
+	suite.Equal([]string{seat4, seat5, seat6, seat7}, seatIDs)
+	otherSplID := idgen.New("spl")
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{SeatingPlanID: &otherSplID}, 100)
+	suite.Empty(seatIDs)
+
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{OrderID: &orderID}, 100)
+	suite.Equal([]string{seat6}, seatIDs)
+
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{UserID: &suite.user.ID, CreatedFrom: &from, CreatedTo: &to}, 100)
+	suite.Equal([]string{seat4, seat5, seat6}, seatIDs)
+
+	createdTo := from.Add(time.Minute)
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{CreatedFrom: &from, CreatedTo: &createdTo}, 100)
+	suite.Equal([]string{seat4, seat5}, seatIDs)
+
+	// org only
+	seatIDs, _ = suite.listSeatLogs(domain.SeatLogsFilter{}, 1000)
+	suite.Subset(seatIDs, []string{seat4, seat5, seat6, seat7})
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{OrgID: idgen.New("org")}, 100)
+	suite.NoError(err)
+	suite.Empty(page.Logs)
+	_, err = suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{}, 100)
+	suite.ErrorIs(err, domain.ErrSeatLogsOrgRequired)
+
+	// the cursor continues after the last log of the previous page
+	seatIDs, cursor := suite.listSeatLogs(domain.SeatLogsFilter{CreatedFrom: &from, CreatedTo: &to}, 3)
+	suite.Equal([]string{seat4, seat5, seat6}, seatIDs)
+	if suite.NotNil(cursor) {
+		seatIDs, cursor = suite.listSeatLogs(domain.SeatLogsFilter{CreatedFrom: &from, CreatedTo: &to, Cursor: cursor}, 3)
+		suite.Equal([]string{seat7}, seatIDs)
+		suite.Nil(cursor)
+	}
+}
+
+func TestSeatLogTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatLogTestSuite))
+}
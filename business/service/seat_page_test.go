@@ -0,0 +1,250 @@
+package service
+
+import (
+	"errors"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatPageTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatPageTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatPageTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+// allPages walks through all pages of the query and returns ids of the returned seats in order
+func (suite *SeatPageTestSuite) allPages(query *domain.SeatsQuery, limit int64) []string {
+	var seatIDs []string
+	page := domain.SeatsPageRequest{Limit: limit, WithTotal: true}
+	for {
+		res, err := suite.service.storage.GetSeatsPage(suite.ctx, query, page)
+		suite.NoError(err)
+		suite.Equal(int64(len(suite.seats)), *res.Total)
+		for _, seat := range res.Seats {
+			seatIDs = append(seatIDs, seat.ID)
+		}
+		if res.NextCursor == nil {
+			return seatIDs
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
+func (suite *SeatPageTestSuite) TestGetSeatsPageReturnsAllSeatsInOrder() {
+	for _, orderBy := range []string{"name", "num"} {
+		for _, desc := range []bool{false, true} {
+			orderBy := orderBy
+			filter := domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID, OrderBy: &orderBy, Desc: desc}
+			seats, err := suite.service.storage.GetSeats(suite.ctx, &filter, 100, 0, nil)
+			suite.NoError(err)
+			expected := make([]string, len(seats))
+			for i, seat := range seats {
+				expected[i] = seat.ID
+			}
+			suite.Equal(expected, suite.allPages(&domain.SeatsQuery{SeatsFilter: filter}, 5))
+		}
+	}
+}
+
+func (suite *SeatPageTestSuite) TestGetSeatsPageRejectsCursorOfDifferentOrder() {
+	orderBy := "num"
+	query := &domain.SeatsQuery{SeatsFilter: domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID, OrderBy: &orderBy}}
+	res, err := suite.service.storage.GetSeatsPage(suite.ctx, query, domain.SeatsPageRequest{Limit: 5})
+	suite.NoError(err)
+	suite.NotNil(res.NextCursor)
+	query.Desc = true
+	_, err = suite.service.storage.GetSeatsPage(suite.ctx, query, domain.SeatsPageRequest{Limit: 5, Cursor: res.NextCursor})
+	suite.ErrorIs(err, domain.ErrInvalidCursor)
+}
+
+func (suite *SeatPageTestSuite) TestListSeatsSortedByMultipleKeys() {
+	query := &domain.SeatsQuery{
+		SeatsFilter: domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID},
+		Sort: []domain.SeatSort{
+			{Key: domain.SeatSortY, Desc: true},
+			{Key: domain.SeatSortX},
+		},
+	}
+	seats, err := suite.service.storage.ListSeats(suite.ctx, query, 100, 0)
+	suite.NoError(err)
+	suite.Len(seats, len(suite.seats))
+	for i := 1; i < len(seats); i++ {
+		prev, seat := seats[i-1], seats[i]
+		suite.True(prev.Y > seat.Y || prev.Y == seat.Y && prev.X <= seat.X)
+	}
+	suite.Equal(pageSeatIDs(seats), suite.allPages(query, 7))
+}
+
+func (suite *SeatPageTestSuite) TestGetSeatsSortedByMultipleKeys() {
+	orderBy := "-y, x"
+	seats, err := suite.service.storage.GetSeats(suite.ctx, &domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID, OrderBy: &orderBy}, 100, 0, nil)
+	suite.NoError(err)
+	suite.Len(seats, len(suite.seats))
+	for i := 1; i < len(seats); i++ {
+		prev, seat := seats[i-1], seats[i]
+		suite.True(prev.Y > seat.Y || prev.Y == seat.Y && prev.X <= seat.X)
+	}
+
+	// Desc reverses every key
+	seats, err = suite.service.storage.GetSeats(suite.ctx, &domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID, OrderBy: &orderBy, Desc: true}, 100, 0, nil)
+	suite.NoError(err)
+	for i := 1; i < len(seats); i++ {
+		prev, seat := seats[i-1], seats[i]
+		suite.True(prev.Y < seat.Y || prev.Y == seat.Y && prev.X >= seat.X)
+	}
+}
+
+func (suite *SeatPageTestSuite) TestListSeatsSortedByRowName() {
+	seatsWithSeatGroups, err := suite.service.storage.GetSeatsWithSeatGroups(suite.ctx, suite.ids(), pageSeatIDs(suite.seats))
+	suite.NoError(err)
+	rowNames := make(map[string]string, len(seatsWithSeatGroups))
+	for _, seat := range seatsWithSeatGroups {
+		rowNames[seat.ID] = seat.RowName
+	}
+
+	for _, desc := range []bool{false, true} {
+		query := &domain.SeatsQuery{
+			SeatsFilter: domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID},
+			Sort:        []domain.SeatSort{{Key: domain.SeatSortRow, Desc: desc}, {Key: domain.SeatSortNum}},
+		}
+		seats, err := suite.service.storage.ListSeats(suite.ctx, query, 100, 0)
+		suite.NoError(err)
+		suite.Len(seats, len(suite.seats))
+		seenRowIDs := make(map[string]bool)
+		for i, seat := range seats {
+			if i > 0 && *seat.SeatRowID != *seats[i-1].SeatRowID {
+				prev, next := rowNames[seats[i-1].ID], rowNames[seat.ID]
+				suite.True(desc && prev >= next || !desc && prev <= next)
+				// seats of a row are kept together
+				suite.False(seenRowIDs[*seat.SeatRowID])
+			}
+			seenRowIDs[*seat.SeatRowID] = true
+		}
+		suite.Equal(pageSeatIDs(seats), suite.allPages(query, 5))
+	}
+}
+
+func (suite *SeatPageTestSuite) TestListSeatsRejectsUnknownSortKey() {
+	orderBy := "unknown"
+	_, err := suite.service.storage.GetSeats(suite.ctx, &domain.SeatsFilter{OrgID: &suite.org.ID, OrderBy: &orderBy}, 100, 0, nil)
+	var sortKeyErr *domain.SeatSortKeyError
+	suite.True(errors.As(err, &sortKeyErr))
+	suite.Equal(domain.SeatSortKey("unknown"), sortKeyErr.Key)
+
+	_, err = suite.service.storage.GetSeatsPage(suite.ctx, &domain.SeatsQuery{
+		SeatsFilter: domain.SeatsFilter{OrgID: &suite.org.ID},
+		Sort:        []domain.SeatSort{{Key: domain.SeatSortRow}, {Key: "seat_row_id"}},
+	}, domain.SeatsPageRequest{Limit: 10})
+	suite.True(errors.As(err, &sortKeyErr))
+}
+
+func (suite *SeatPageTestSuite) TestListSeatsFilters() {
+	offeredSeatIDs := []string{suite.seats[6].ID, suite.seats[7].ID}
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID}, domain.UpdateSeatStatus{
+		SeatIDs:    offeredSeatIDs,
+		StatusCode: domain.SeatStatusOffered,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	filter := domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID}
+
+	seats, err := suite.service.storage.ListSeats(suite.ctx, &domain.SeatsQuery{
+		SeatsFilter:          filter,
+		SeatAttributesFilter: domain.SeatAttributesFilter{StatusCodes: []domain.SeatStatus{domain.SeatStatusOffered, domain.SeatStatusSold}},
+	}, 100, 0)
+	suite.NoError(err)
+	suite.ElementsMatch(offeredSeatIDs, pageSeatIDs(seats))
+
+	seats, err = suite.service.storage.ListSeats(suite.ctx, &domain.SeatsQuery{
+		SeatsFilter:          filter,
+		SeatAttributesFilter: domain.SeatAttributesFilter{SeatRowID: suite.seats[0].SeatRowID},
+	}, 100, 0)
+	suite.NoError(err)
+	suite.NotEmpty(seats)
+	suite.Less(len(seats), len(suite.seats))
+	suite.Contains(pageSeatIDs(seats), suite.seats[0].ID)
+	for _, seat := range seats {
+		suite.Equal(*suite.seats[0].SeatRowID, *seat.SeatRowID)
+	}
+
+	// all seats of the suite have a price category
+	noPriceCategory := ""
+	seats, err = suite.service.storage.ListSeats(suite.ctx, &domain.SeatsQuery{
+		SeatsFilter:          filter,
+		SeatAttributesFilter: domain.SeatAttributesFilter{PriceCategoryID: &noPriceCategory},
+	}, 100, 0)
+	suite.NoError(err)
+	suite.Empty(seats)
+}
+
+func (suite *SeatPageTestSuite) TestGetSeatsFiltersByAttributes() {
+	sgs, err := suite.service.GetSeatGroups(suite.ctx, &domain.SeatGroupsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID})
+	suite.NoError(err)
+	if !suite.NotEmpty(sgs) {
+		return
+	}
+	groupID := sgs[0].ID
+	wheelchair := true
+	linkedSeatID := suite.seats[11].ID
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{
+		{ID: suite.seats[8].ID, SeatBlockID: &groupID, SeatGateID: &groupID, Wheelchair: &wheelchair},
+		{ID: suite.seats[10].ID, LinkedSeatID: &linkedSeatID},
+	}, time.Now())
+	suite.NoError(err)
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:        []string{suite.seats[9].ID},
+		StatusCode:     domain.SeatStatusSold,
+		AddOrderID:     &orderID,
+		AddOrderItemID: &orderItemID,
+		UpdatedAt:      time.Now(),
+	})
+	suite.NoError(err)
+
+	hasLinkedSeat := true
+	filter := &domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID}
+	for _, tc := range []struct {
+		name       string
+		attributes domain.SeatAttributesFilter
+		seatIDs    []string
+	}{
+		{"block", domain.SeatAttributesFilter{SeatBlockID: &groupID}, []string{suite.seats[8].ID}},
+		{"gate", domain.SeatAttributesFilter{SeatGateID: &groupID}, []string{suite.seats[8].ID}},
+		{"wheelchair", domain.SeatAttributesFilter{Wheelchair: &wheelchair}, []string{suite.seats[8].ID}},
+		{"order", domain.SeatAttributesFilter{OrderID: &orderID}, []string{suite.seats[9].ID}},
+		{"linked seat", domain.SeatAttributesFilter{HasLinkedSeat: &hasLinkedSeat}, []string{suite.seats[10].ID}},
+	} {
+		seats, err := suite.service.storage.GetSeats(suite.ctx, filter, 100, 0, &tc.attributes)
+		suite.NoError(err, tc.name)
+		suite.Equal(tc.seatIDs, pageSeatIDs(seats), tc.name)
+	}
+
+	// without a linked seat
+	hasLinkedSeat = false
+	seats, err := suite.service.storage.GetSeats(suite.ctx, filter, 100, 0, &domain.SeatAttributesFilter{HasLinkedSeat: &hasLinkedSeat})
+	suite.NoError(err)
+	suite.Len(seats, len(suite.seats)-1)
+	suite.NotContains(pageSeatIDs(seats), suite.seats[10].ID)
+}
+
+func pageSeatIDs(seats []domain.Seat) []string {
+	seatIDs := make([]string, len(seats))
+	for i, seat := range seats {
+		seatIDs[i] = seat.ID
+	}
+	return seatIDs
+}
+
+func TestSeatPageTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatPageTestSuite))
+}
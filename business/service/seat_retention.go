@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/proj/business/domain"
+)
+
+// PurgeDeletedSeats hard deletes seats of the org which were soft deleted longer than retention ago,
+// seat logs of purged seats are deleted when withLogs is set
+func (s *Service) PurgeDeletedSeats(ctx context.Context, orgID string, retention time.Duration, withLogs bool) (*domain.SeatPurgeResult, error) {
+	if retention <= 0 {
+		return nil, fmt.Errorf("invalid seat retention %v", retention)
+	}
+	now := time.Now()
+	res, err := s.storage.PurgeDeletedSeats(ctx, &domain.IDs{OrgID: orgID}, now.Add(-retention), withLogs, now)
+	if err != nil {
+		return nil, fmt.Errorf("error while purging deleted seats %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatRetentionTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatRetentionTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatRetentionTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatRetentionTestSuite) deletedSeatIDs() []string {
+	seats, err := suite.service.storage.ListSeats(suite.ctx, &domain.SeatsQuery{
+		SeatsFilter:          domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID},
+		SeatAttributesFilter: domain.SeatAttributesFilter{Deleted: domain.SeatDeletedOnly},
+	}, 100, 0)
+	suite.NoError(err)
+	seatIDs := make([]string, len(seats))
+	for i, seat := range seats {
+		seatIDs[i] = seat.ID
+	}
+	return seatIDs
+}
+
+func (suite *SeatRetentionTestSuite) TestRestoreSeats() {
+	seatIDs := []string{suite.seats[0].ID, suite.seats[1].ID}
+	err := suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), seatIDs, time.Now())
+	suite.NoError(err)
+	suite.Subset(suite.deletedSeatIDs(), seatIDs)
+
+	restoredSeatIDs, err := suite.service.storage.RestoreSeats(suite.ctx, suite.ids(), seatIDs, time.Now())
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, restoredSeatIDs)
+	suite.NotContains(suite.deletedSeatIDs(), seatIDs[0])
+
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{OrgID: suite.org.ID, SeatID: &seatIDs[0]}, 100)
+	suite.NoError(err)
+	lastLog := page.Logs[len(page.Logs)-1]
+	suite.Equal("restored", lastLog.Action)
+	suite.Contains(lastLog.Changes, domain.SeatFieldDeletedAt)
+}
+
+func (suite *SeatRetentionTestSuite) TestRestoreSeatsRejectsUnknownSeat() {
+	seatID := suite.seats[9].ID
+	err := suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{seatID}, time.Now())
+	suite.NoError(err)
+
+	_, err = suite.service.storage.RestoreSeats(suite.ctx, suite.ids(), []string{seatID, "missing"}, time.Now())
+	suite.ErrorIs(err, domain.ErrSeatNotFound)
+	var seatErr *domain.SeatError
+	suite.ErrorAs(err, &seatErr)
+	suite.Equal([]string{"missing"}, seatErr.SeatIDs)
+	// nothing is restored
+	suite.Contains(suite.deletedSeatIDs(), seatID)
+}
+
+func (suite *SeatRetentionTestSuite) TestDeleteSeatsDropsTheirHolds() {
+	deletedSeatID, heldSeatID := suite.seats[10].ID, suite.seats[11].ID
+	now := time.Now()
+	hold, err := suite.service.storage.HoldSeats(suite.ctx, suite.ids(), []string{deletedSeatID, heldSeatID}, time.Minute, now)
+	suite.NoError(err)
+	err = suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{deletedSeatID}, now)
+	suite.NoError(err)
+
+	activeHold, err := suite.service.storage.GetSeatHold(suite.ctx, suite.ids(), hold.Token, now)
+	suite.NoError(err)
+	suite.Equal([]string{heldSeatID}, activeHold.SeatIDs)
+	// the rest of the hold can still be released
+	err = suite.service.storage.ReleaseSeatHold(suite.ctx, suite.ids(), hold.Token, now)
+	suite.NoError(err)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: heldSeatID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusAvailable, seat.StatusCode)
+}
+
+func (suite *SeatRetentionTestSuite) TestGetSeatsDeleted() {
+	seatID := suite.seats[11].ID
+	err := suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{seatID}, time.Now())
+	suite.NoError(err)
+
+	filter := &domain.SeatsFilter{OrgID: &suite.org.ID, SeatingPlanID: &suite.spl.ID}
+	seats, err := suite.service.storage.GetSeats(suite.ctx, filter, 100, 0, nil)
+	suite.NoError(err)
+	suite.NotContains(pageSeatIDs(seats), seatID)
+
+	seats, err = suite.service.storage.GetSeats(suite.ctx, filter, 100, 0, &domain.SeatAttributesFilter{Deleted: domain.SeatDeletedInclude})
+	suite.NoError(err)
+	suite.Len(seats, len(suite.seats))
+	suite.Contains(pageSeatIDs(seats), seatID)
+
+	seats, err = suite.service.storage.GetSeats(suite.ctx, filter, 100, 0, &domain.SeatAttributesFilter{Deleted: domain.SeatDeletedOnly})
+	suite.NoError(err)
+	suite.Contains(pageSeatIDs(seats), seatID)
+	for _, seat := range seats {
+		suite.NotNil(seat.DeletedAt, seat.ID)
+	}
+}
+
+func (suite *SeatRetentionTestSuite) TestPurgeDeletedSeats() {
+	seatIDs := []string{suite.seats[2].ID, suite.seats[3].ID}
+	err := suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), seatIDs, time.Now().Add(-48*time.Hour))
+	suite.NoError(err)
+
+	// seats deleted later than the retention are kept
+	res, err := suite.service.PurgeDeletedSeats(suite.ctx, suite.org.ID, 72*time.Hour, true)
+	suite.NoError(err)
+	suite.Empty(res.PurgedSeatIDs)
+
+	res, err = suite.service.PurgeDeletedSeats(suite.ctx, suite.org.ID, 24*time.Hour, true)
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, res.PurgedSeatIDs)
+	suite.NotContains(suite.deletedSeatIDs(), seatIDs[0])
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{OrgID: suite.org.ID, SeatID: &seatIDs[0]}, 100)
+	suite.NoError(err)
+	suite.Empty(page.Logs)
+}
+
+func (suite *SeatRetentionTestSuite) TestPurgeDeletedSeatsRefusesOrderedSeats() {
+	orderedSeatID, linkedSeatID, soldSeatID := suite.seats[4].ID, suite.seats[5].ID, suite.seats[6].ID
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:        []string{orderedSeatID},
+		StatusCode:     domain.SeatStatusSold,
+		AddOrderID:     &orderID,
+		AddOrderItemID: &orderItemID,
+		UpdatedAt:      time.Now(),
+	})
+	suite.NoError(err)
+	// the order is refunded, the seat logs still reference it
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:       []string{orderedSeatID},
+		StatusCode:    domain.SeatStatusAvailable,
+		RemoveOrderID: true,
+		UpdatedAt:     time.Now(),
+	})
+	suite.NoError(err)
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: soldSeatID, LinkedSeatID: &linkedSeatID}}, time.Now())
+	suite.NoError(err)
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{soldSeatID},
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	err = suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{orderedSeatID, linkedSeatID}, time.Now().Add(-48*time.Hour))
+	suite.NoError(err)
+
+	res, err := suite.service.PurgeDeletedSeats(suite.ctx, suite.org.ID, 24*time.Hour, false)
+	suite.NoError(err)
+	suite.Subset(res.RefusedSeatIDs, []string{orderedSeatID, linkedSeatID})
+	suite.NotContains(res.PurgedSeatIDs, orderedSeatID)
+	suite.NotContains(res.PurgedSeatIDs, linkedSeatID)
+	suite.Subset(suite.deletedSeatIDs(), []string{orderedSeatID, linkedSeatID})
+}
+
+func (suite *SeatRetentionTestSuite) TestPurgeDeletedSeatsUnlinksLiveSeats() {
+	seatID, linkedSeatID := suite.seats[7].ID, suite.seats[8].ID
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: seatID, LinkedSeatID: &linkedSeatID}}, time.Now())
+	suite.NoError(err)
+	err = suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{linkedSeatID}, time.Now().Add(-48*time.Hour))
+	suite.NoError(err)
+
+	res, err := suite.service.PurgeDeletedSeats(suite.ctx, suite.org.ID, 24*time.Hour, false)
+	suite.NoError(err)
+	suite.Contains(res.PurgedSeatIDs, linkedSeatID)
+
+	// the live seat is freed and the unlinking is logged
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seatID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Empty(seat.LinkedSeatID)
+	suite.Equal(domain.SeatStatusAvailable, seat.StatusCode)
+	page, err := suite.service.storage.ListSeatLogs(suite.ctx, &domain.SeatLogsFilter{OrgID: suite.org.ID, SeatID: &seatID}, 100)
+	suite.NoError(err)
+	lastLog := page.Logs[len(page.Logs)-1]
+	suite.Equal(domain.SeatFieldChange{Old: linkedSeatID, New: nil}, lastLog.Changes[domain.SeatFieldLinkedSeatID])
+}
+
+func TestSeatRetentionTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatRetentionTestSuite))
+}
@@ -6,12 +6,29 @@ import (
 	"fmt"
 	"github.com/proj/business/domain"
 	"github.com/proj/business/storage"
+	"sort"
 )
 
+// ValidateSeatRules checks that the requested seats may be ordered together. Wheelchair spaces of orgs restricting
+// their ticket types are refused, use ValidateSeatRulesWithTickets to order them with accessibility tickets.
 func (s *Service) ValidateSeatRules(ctx context.Context, orgID string, splIDBookAllSeatsInGroup map[string]string, requestedSeatsGroupedByRowsGroupedBySpl map[string]map[string][]domain.Seat) error {
+	return s.ValidateSeatRulesWithTickets(ctx, orgID, splIDBookAllSeatsInGroup, requestedSeatsGroupedByRowsGroupedBySpl, nil)
+}
+
+// ValidateSeatRulesWithTickets checks that the requested seats may be ordered together with the requested tickets,
+// nil tickets refuse wheelchair spaces of orgs restricting their ticket types
+func (s *Service) ValidateSeatRulesWithTickets(ctx context.Context, orgID string, splIDBookAllSeatsInGroup map[string]string, requestedSeatsGroupedByRowsGroupedBySpl map[string]map[string][]domain.Seat, tickets *domain.SeatTickets) error {
 	ids := &domain.IDs{
 		OrgID: orgID,
 	}
+	err := checkLinkedSeatGroups(ctx, s.storage, ids, requestedSeatsGroupedByRowsGroupedBySpl)
+	if err != nil {
+		return err
+	}
+	err = checkSeatAccessibilityRules(ctx, s.storage, ids, tickets, requestedSeatsGroupedByRowsGroupedBySpl)
+	if err != nil {
+		return err
+	}
 	availableRowSeats, requestedRowsSeats, err := getRowSeatsForSeatingPlanAndRequestedSeats(ctx, s.storage, ids, requestedSeatsGroupedByRowsGroupedBySpl)
 	if err != nil {
 		return err
@@ -44,6 +61,41 @@ func (s *Service) ValidateSeatRules(ctx context.Context, orgID string, splIDBook
 	return nil
 }
 
+// checkLinkedSeatGroups checks that linked seat groups are requested as a whole
+func checkLinkedSeatGroups(ctx context.Context, s *storage.Storage, ids *domain.IDs, requestedSeatsGroupedByRowsGroupedBySpl map[string]map[string][]domain.Seat) error {
+	requestedSeatIDs := make(map[string]bool)
+	for _, rows := range requestedSeatsGroupedByRowsGroupedBySpl {
+		for _, rowSeats := range rows {
+			for _, seat := range rowSeats {
+				requestedSeatIDs[seat.ID] = true
+			}
+		}
+	}
+	seatIDs := make([]string, 0, len(requestedSeatIDs))
+	for seatID := range requestedSeatIDs {
+		seatIDs = append(seatIDs, seatID)
+	}
+	linkedSeatGroups, err := s.GetLinkedSeatGroups(ctx, ids, seatIDs)
+	if err != nil {
+		return fmt.Errorf("error while querying linked seats %w", err)
+	}
+	var missingSeatIDs []string
+	for _, group := range linkedSeatGroups {
+		for _, seatID := range group.SeatIDs {
+			if !requestedSeatIDs[seatID] {
+				missingSeatIDs = append(missingSeatIDs, seatID)
+			}
+		}
+	}
+	if len(missingSeatIDs) > 0 {
+		return &domain.SeatRuleLinkedSeatsError{
+			OrigError:      errors.New("linked seats must be ordered together"),
+			MissingSeatIDs: missingSeatIDs,
+		}
+	}
+	return nil
+}
+
 func getRowSeatsForSeatingPlanAndRequestedSeats(ctx context.Context, s *storage.Storage, ids *domain.IDs, requestedSeatsGroupedByRowsGroupedBySpl map[string]map[string][]domain.Seat) (availableRowSeats, requestedRowSeats map[string][]domain.Seat, err error) {
 	requestedRowSeats = make(map[string][]domain.Seat)
 	availableRowSeats = make(map[string][]domain.Seat)
@@ -53,6 +105,14 @@ func getRowSeatsForSeatingPlanAndRequestedSeats(ctx context.Context, s *storage.
 			if err != nil {
 				return nil, nil, fmt.Errorf("error while querying seats %w", err)
 			}
+			linkLockedSeats, err := getLinkLockedRowSeats(ctx, s, ids.OrgID, splID, rowID)
+			if err != nil {
+				return nil, nil, err
+			}
+			seatsForRow = append(seatsForRow, linkLockedSeats...)
+			sort.SliceStable(seatsForRow, func(i, j int) bool {
+				return seatsForRow[i].Num < seatsForRow[j].Num
+			})
 			availableRowSeats[rowID] = seatsForRow
 			requestedRowSeats[rowID] = requestedSeatsGroupedByRowsGroupedBySpl[splID][rowID]
 		}
@@ -60,6 +120,33 @@ func getRowSeatsForSeatingPlanAndRequestedSeats(ctx context.Context, s *storage.
 	return availableRowSeats, requestedRowSeats, nil
 }
 
+// getLinkLockedRowSeats returns seats of the row which are locked because they're linked, they're available
+// for order together with their linked seat group
+func getLinkLockedRowSeats(ctx context.Context, s *storage.Storage, orgID, splID, rowID string) ([]domain.Seat, error) {
+	hasLinkedSeat := true
+	query := &domain.SeatsQuery{
+		SeatsFilter: domain.SeatsFilter{OrgID: &orgID, SeatingPlanID: &splID},
+		SeatAttributesFilter: domain.SeatAttributesFilter{
+			StatusCodes:   []domain.SeatStatus{domain.SeatStatusLocked},
+			SeatRowID:     &rowID,
+			HasLinkedSeat: &hasLinkedSeat,
+		},
+	}
+	page := domain.SeatsPageRequest{Limit: seatsPageSize}
+	var seats []domain.Seat
+	for {
+		res, err := s.GetSeatsPage(ctx, query, page)
+		if err != nil {
+			return nil, fmt.Errorf("error while querying linked row seats %w", err)
+		}
+		seats = append(seats, res.Seats...)
+		if res.NextCursor == nil {
+			return seats, nil
+		}
+		page.Cursor = res.NextCursor
+	}
+}
+
 func getSeatsWithPriceCategoriesForSpl(ctx context.Context, s *storage.Storage, ids *domain.IDs, requestedSeatsGroupedByRowsGroupedBySpl map[string]map[string][]domain.Seat, onlyAvailable bool) (map[string][]domain.SeatsPerPriceCategories, error) {
 	seatsByPriceCategoriesPerSpl := make(map[string][]domain.SeatsPerPriceCategories)
 	for splID := range requestedSeatsGroupedByRowsGroupedBySpl {
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/proj/business/domain"
+)
+
+// SeatScorer scores a seat proposed as best available, lower scores are better
+type SeatScorer interface {
+	ScoreSeat(seat domain.Seat, row SeatScoringRow) float64
+}
+
+// SeatScoringRow describes the row of a scored seat
+type SeatScoringRow struct {
+	RowID   string
+	BlockID string
+	// MinX and MaxX are horizontal positions of the first and the last seats of the row
+	MinX float64
+	MaxX float64
+}
+
+// SeatScorerFunc is a function used as SeatScorer
+type SeatScorerFunc func(seat domain.Seat, row SeatScoringRow) float64
+
+func (f SeatScorerFunc) ScoreSeat(seat domain.Seat, row SeatScoringRow) float64 {
+	return f(seat, row)
+}
+
+// DistanceScorer scores seats by their euclidean distance from the closest reference point, the distance
+// from a point is divided by the point weight. Seats score 0 when there are no reference points.
+type DistanceScorer []domain.SeatReferencePoint
+
+func (sc DistanceScorer) ScoreSeat(seat domain.Seat, _ SeatScoringRow) float64 {
+	if len(sc) == 0 {
+		return 0
+	}
+	score := math.Inf(1)
+	for _, point := range sc {
+		score = math.Min(score, math.Hypot(float64(seat.X-point.X), float64(seat.Y-point.Y))/point.Weight)
+	}
+	return score
+}
+
+// CentralityScorer scores seats by their distance from the center of the row, from 0 in the center to 1 at the ends
+type CentralityScorer struct{}
+
+func (CentralityScorer) ScoreSeat(seat domain.Seat, row SeatScoringRow) float64 {
+	halfWidth := (row.MaxX - row.MinX) / 2
+	if halfWidth <= 0 {
+		return 0
+	}
+	return math.Min(math.Abs(float64(seat.X)-row.MinX-halfWidth)/halfWidth, 1)
+}
+
+// AisleScorer scores seats by their distance from the closest end of the row, from 0 at the ends to 1 in the center
+type AisleScorer struct{}
+
+func (AisleScorer) ScoreSeat(seat domain.Seat, row SeatScoringRow) float64 {
+	return 1 - CentralityScorer{}.ScoreSeat(seat, row)
+}
+
+// RowPreferenceScorer scores seats by the preference of their row, rows without preference score 0
+type RowPreferenceScorer map[string]float64
+
+func (sc RowPreferenceScorer) ScoreSeat(_ domain.Seat, row SeatScoringRow) float64 {
+	return sc[row.RowID]
+}
+
+// BlockBiasScorer scores seats by the bias of their block, blocks without bias score 0
+type BlockBiasScorer map[string]float64
+
+func (sc BlockBiasScorer) ScoreSeat(_ domain.Seat, row SeatScoringRow) float64 {
+	return sc[row.BlockID]
+}
+
+// WeightedSeatScorer is a scorer of WeightedSeatScorers
+type WeightedSeatScorer struct {
+	Scorer SeatScorer
+	Weight float64
+}
+
+// WeightedSeatScorers sums up weighted scores of the scorers
+type WeightedSeatScorers []WeightedSeatScorer
+
+func (sc WeightedSeatScorers) ScoreSeat(seat domain.Seat, row SeatScoringRow) float64 {
+	var score float64
+	for _, weighted := range sc {
+		score += weighted.Weight * weighted.Scorer.ScoreSeat(seat, row)
+	}
+	return score
+}
+
+// NewSeatScorer composes the built-in scorers with weights of the config, scorers of zero weight are left out.
+// Configs which don't tell seats apart, e.g. a distance weight without reference points, use the default config.
+func NewSeatScorer(config *domain.SeatScoringConfig) SeatScorer {
+	if !scoresSeats(config) {
+		config = domain.DefaultSeatScoringConfig()
+	}
+	var scorers WeightedSeatScorers
+	add := func(scorer SeatScorer, weight float64) {
+		if weight != 0 {
+			scorers = append(scorers, WeightedSeatScorer{Scorer: scorer, Weight: weight})
+		}
+	}
+	add(DistanceScorer(config.ReferencePoints), config.DistanceWeight)
+	add(CentralityScorer{}, config.CentralityWeight)
+	add(AisleScorer{}, config.AisleWeight)
+	add(RowPreferenceScorer(config.RowPreferences), config.RowWeight)
+	add(BlockBiasScorer(config.BlockBiases), config.BlockWeight)
+	return scorers
+}
+
+// scoresSeats tells whether any weighted scorer of the config scores seats differently
+func scoresSeats(config *domain.SeatScoringConfig) bool {
+	return (config.DistanceWeight != 0 && len(config.ReferencePoints) > 0) ||
+		config.CentralityWeight != 0 ||
+		config.AisleWeight != 0 ||
+		(config.RowWeight != 0 && len(config.RowPreferences) > 0) ||
+		(config.BlockWeight != 0 && len(config.BlockBiases) > 0)
+}
+
+// seatScoreLowerBound returns the lowest score the scorer of the config can give seats which are at least distance
+// from the closest reference point, the distance divided by the weight of the point like DistanceScorer does
+func seatScoreLowerBound(config *domain.SeatScoringConfig, distance float64) float64 {
+	if !scoresSeats(config) {
+		config = domain.DefaultSeatScoringConfig()
+	}
+	var bound float64
+	if len(config.ReferencePoints) > 0 {
+		if config.DistanceWeight < 0 {
+			return math.Inf(-1)
+		}
+		bound += config.DistanceWeight * distance
+	}
+	bound += math.Min(0, config.CentralityWeight) + math.Min(0, config.AisleWeight)
+	minScore := func(weight float64, scores map[string]float64) float64 {
+		var score float64
+		for _, s := range scores {
+			score = math.Min(score, weight*s)
+		}
+		return score
+	}
+	return bound + minScore(config.RowWeight, config.RowPreferences) + minScore(config.BlockWeight, config.BlockBiases)
+}
+
+// GetSeatScoringConfig returns the best seats scoring config of the seating plan ids.SplID
+func (s *Service) GetSeatScoringConfig(ctx context.Context, ids *domain.IDs) (*domain.SeatScoringConfig, error) {
+	config, err := s.storage.GetSeatScoringConfig(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting seat scoring config %w", err)
+	}
+	return config, nil
+}
+
+// SetSeatScoringConfig sets the best seats scoring config of the seating plan ids.SplID
+func (s *Service) SetSeatScoringConfig(ctx context.Context, ids *domain.IDs, config *domain.SeatScoringConfig) error {
+	scores := []float64{config.DistanceWeight, config.CentralityWeight, config.AisleWeight, config.RowWeight, config.BlockWeight, config.OffsetWeight}
+	for _, score := range config.RowPreferences {
+		scores = append(scores, score)
+	}
+	for _, score := range config.BlockBiases {
+		scores = append(scores, score)
+	}
+	for _, score := range scores {
+		if math.IsNaN(score) || math.IsInf(score, 0) {
+			return fmt.Errorf("invalid seat scoring weight %v", score)
+		}
+	}
+	names := make(map[string]bool, len(config.ReferencePoints))
+	for _, point := range config.ReferencePoints {
+		if point.Name == "" || names[point.Name] {
+			return fmt.Errorf("invalid or duplicate seat reference point name %q", point.Name)
+		}
+		names[point.Name] = true
+		if !(point.Weight > 0) || math.IsInf(point.Weight, 0) {
+			return fmt.Errorf("invalid weight %v of seat reference point %q", point.Weight, point.Name)
+		}
+	}
+	if !scoresSeats(config) {
+		return fmt.Errorf("seat scoring config scores all seats the same, it needs reference points or other weights")
+	}
+	if err := s.storage.SetSeatScoringConfig(ctx, ids, config, time.Now()); err != nil {
+		return fmt.Errorf("error while setting seat scoring config %w", err)
+	}
+	return nil
+}
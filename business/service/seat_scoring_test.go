@@ -0,0 +1,143 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"testing"
+)
+
+type SeatScoringTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatScoringTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatScoringTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+// TestCreateSeatScoringConfigOfAnotherOrgPlan runs before the seating plan of the suite has a config
+func (suite *SeatScoringTestSuite) TestCreateSeatScoringConfigOfAnotherOrgPlan() {
+	otherIDs := &domain.IDs{OrgID: idgen.New("org"), UserID: suite.user.ID, SplID: suite.spl.ID}
+	err := suite.service.SetSeatScoringConfig(suite.ctx, otherIDs, &domain.SeatScoringConfig{CentralityWeight: 5})
+	suite.ErrorIs(err, domain.ErrSeatScoringPlanNotFound)
+	config, err := suite.service.GetSeatScoringConfig(suite.ctx, otherIDs)
+	suite.NoError(err)
+	suite.Equal(domain.DefaultSeatScoringConfig(), config)
+	err = suite.service.SetSeatScoringConfig(suite.ctx, &domain.IDs{OrgID: suite.org.ID, UserID: suite.user.ID, SplID: idgen.New("spl")}, domain.DefaultSeatScoringConfig())
+	suite.ErrorIs(err, domain.ErrSeatScoringPlanNotFound)
+
+	// the org of the seating plan can still create its config
+	err = suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), domain.DefaultSeatScoringConfig())
+	suite.NoError(err)
+	config, err = suite.service.GetSeatScoringConfig(suite.ctx, suite.ids())
+	suite.NoError(err)
+	suite.Equal(domain.DefaultSeatScoringConfig(), config)
+}
+
+func (suite *SeatScoringTestSuite) TestGetSeatScoringConfigDefault() {
+	config, err := suite.service.GetSeatScoringConfig(suite.ctx, &domain.IDs{OrgID: suite.org.ID, SplID: "missing"})
+	suite.NoError(err)
+	suite.Equal(domain.DefaultSeatScoringConfig(), config)
+}
+
+func (suite *SeatScoringTestSuite) TestSetSeatScoringConfig() {
+	config := &domain.SeatScoringConfig{
+		DistanceWeight: 0.5,
+		ReferencePoints: []domain.SeatReferencePoint{
+			{Name: "home goal", X: 0, Y: 50, Weight: 1},
+			{Name: "away goal", X: 200, Y: 50, Weight: 1.5},
+		},
+		CentralityWeight: 2,
+		RowWeight:        1,
+		RowPreferences:   map[string]float64{*suite.seats[0].SeatRowID: -10},
+		BlockWeight:      1,
+		BlockBiases:      map[string]float64{},
+		OffsetWeight:     0.5,
+	}
+	err := suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), config)
+	suite.NoError(err)
+	stored, err := suite.service.GetSeatScoringConfig(suite.ctx, suite.ids())
+	suite.NoError(err)
+	suite.Equal(config, stored)
+
+	// the seating plan of another org keeps its config
+	err = suite.service.SetSeatScoringConfig(suite.ctx, &domain.IDs{OrgID: idgen.New("org"), UserID: suite.user.ID, SplID: suite.spl.ID}, domain.DefaultSeatScoringConfig())
+	suite.ErrorIs(err, domain.ErrSeatScoringPlanNotFound)
+	stored, err = suite.service.GetSeatScoringConfig(suite.ctx, suite.ids())
+	suite.NoError(err)
+	suite.Equal(config, stored)
+
+	err = suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), &domain.SeatScoringConfig{DistanceWeight: math.Inf(1)})
+	suite.Error(err)
+	err = suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), &domain.SeatScoringConfig{
+		DistanceWeight:  1,
+		ReferencePoints: []domain.SeatReferencePoint{{Name: "stage", Weight: 0}},
+	})
+	suite.Error(err)
+	// the distance from no reference points scores all seats the same
+	err = suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), &domain.SeatScoringConfig{DistanceWeight: 1})
+	suite.Error(err)
+	err = suite.service.SetSeatScoringConfig(suite.ctx, suite.ids(), domain.DefaultSeatScoringConfig())
+	suite.NoError(err)
+}
+
+func (suite *SeatScoringTestSuite) TestRowPositionScorers() {
+	row := SeatScoringRow{MinX: 10, MaxX: 50}
+	suite.Equal(0.0, CentralityScorer{}.ScoreSeat(domain.Seat{X: 30}, row))
+	suite.Equal(1.0, CentralityScorer{}.ScoreSeat(domain.Seat{X: 10}, row))
+	suite.Equal(0.5, CentralityScorer{}.ScoreSeat(domain.Seat{X: 40}, row))
+	suite.Equal(1.0, AisleScorer{}.ScoreSeat(domain.Seat{X: 30}, row))
+	suite.Equal(0.0, AisleScorer{}.ScoreSeat(domain.Seat{X: 50}, row))
+}
+
+func (suite *SeatScoringTestSuite) TestBlockBiasOutweighsDistance() {
+	// the side stalls are closer to the stage, but the center balcony is preferred
+	stalls := bestSeatsTestRow("stalls", 1, 4)
+	stalls.blockID = "side-stalls"
+	balcony := bestSeatsTestRow("balcony", 1, 4)
+	balcony.blockID, balcony.y = "center-balcony", 100
+	for i := range balcony.seats {
+		balcony.seats[i].Y = 100
+	}
+	stage := []domain.SeatReferencePoint{{Name: "stage", Weight: 1}}
+	config := &domain.SeatScoringConfig{
+		DistanceWeight:  1,
+		ReferencePoints: stage,
+		BlockWeight:     1,
+		BlockBiases:     map[string]float64{"center-balcony": -200},
+	}
+	rows := []bestSeatsRow{stalls, balcony}
+	seats, ok := findBestRowBlock(rows, nil, "", 4, NewSeatScorer(&domain.SeatScoringConfig{DistanceWeight: 1, ReferencePoints: stage}))
+	suite.True(ok)
+	suite.Equal("stalls", *seats[0].SeatRowID)
+	seats, ok = findBestRowBlock(rows, nil, "", 4, NewSeatScorer(config))
+	suite.True(ok)
+	suite.Equal("balcony", *seats[0].SeatRowID)
+}
+
+func (suite *SeatScoringTestSuite) TestNewSeatScorerFallsBackToDefaultConfig() {
+	row := bestSeatsTestRow("r1", 1, 7)
+	seats, ok := findBestRowBlock([]bestSeatsRow{row}, nil, "", 3, NewSeatScorer(&domain.SeatScoringConfig{DistanceWeight: 1}))
+	suite.True(ok)
+	suite.Equal([]int32{3, 4, 5}, bestSeatsTestNums(seats))
+}
+
+func (suite *SeatScoringTestSuite) TestDistanceScorerUsesClosestReferencePoint() {
+	scorer := DistanceScorer{
+		{Name: "home goal", X: 0, Y: 0, Weight: 1},
+		{Name: "away goal", X: 100, Y: 0, Weight: 2},
+	}
+	suite.Equal(10.0, scorer.ScoreSeat(domain.Seat{X: 10}, SeatScoringRow{}))
+	suite.Equal(15.0, scorer.ScoreSeat(domain.Seat{X: 70}, SeatScoringRow{}))
+	suite.Equal(0.0, DistanceScorer{}.ScoreSeat(domain.Seat{X: 70}, SeatScoringRow{}))
+}
+
+func TestSeatScoringTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatScoringTestSuite))
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatStatusTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatStatusTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatStatusTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatStatusTestSuite) TestUpdateSeatsStatusReturnsPreviousStatuses() {
+	seatIDs := []string{suite.seats[0].ID, suite.seats[1].ID}
+	change, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    seatIDs,
+		StatusCode: domain.SeatStatusOffered,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, change.SeatIDs)
+	suite.Equal(domain.SeatStatusAvailable, change.PreviousStatuses[seatIDs[0]])
+	suite.Equal(domain.SeatStatusAvailable, change.PreviousStatuses[seatIDs[1]])
+
+	change, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    seatIDs,
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusOffered, change.PreviousStatuses[seatIDs[0]])
+	for _, seatID := range seatIDs {
+		seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seatID, OrgID: suite.org.ID})
+		suite.NoError(err)
+		suite.Equal(domain.SeatStatusSold, seat.StatusCode)
+	}
+}
+
+func (suite *SeatStatusTestSuite) TestUpdateSeatsStatusRejectsSoldToAvailableWithoutRefund() {
+	seatIDs := []string{suite.seats[2].ID}
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    seatIDs,
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    seatIDs,
+		StatusCode: domain.SeatStatusAvailable,
+		UpdatedAt:  time.Now(),
+	})
+	var transitionErr *domain.SeatStatusTransitionError
+	suite.ErrorAs(err, &transitionErr)
+	suite.Equal(suite.seats[2].ID, transitionErr.SeatID)
+	suite.Equal(domain.SeatStatusSold, transitionErr.From)
+	suite.Equal(domain.SeatStatusAvailable, transitionErr.To)
+
+	// refund removes the order and makes the seat available again
+	change, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:       seatIDs,
+		StatusCode:    domain.SeatStatusAvailable,
+		RemoveOrderID: true,
+		UpdatedAt:     time.Now(),
+	})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusSold, change.PreviousStatuses[suite.seats[2].ID])
+}
+
+func (suite *SeatStatusTestSuite) TestUpdateSeatsRejectsSoldToAvailable() {
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.seats[3].ID},
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+	available := int32(domain.SeatStatusAvailable)
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: suite.seats[3].ID, StatusCode: &available}}, time.Now())
+	var transitionErr *domain.SeatStatusTransitionError
+	suite.ErrorAs(err, &transitionErr)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[3].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusSold, seat.StatusCode)
+}
+
+func (suite *SeatStatusTestSuite) TestValidateSeatStatusTransition() {
+	tests := []struct {
+		from, to       domain.SeatStatus
+		refund, linked bool
+		allowed        bool
+	}{
+		{from: domain.SeatStatusAvailable, to: domain.SeatStatusAvailable, allowed: true},
+		{from: domain.SeatStatusAvailable, to: domain.SeatStatusOffered, allowed: true},
+		{from: domain.SeatStatusAvailable, to: domain.SeatStatusSold, allowed: true},
+		{from: domain.SeatStatusAvailable, to: domain.SeatStatusLocked, allowed: true},
+		{from: domain.SeatStatusOffered, to: domain.SeatStatusAvailable, allowed: true},
+		{from: domain.SeatStatusOffered, to: domain.SeatStatusSold, allowed: true},
+		{from: domain.SeatStatusOffered, to: domain.SeatStatusLocked, allowed: false},
+		{from: domain.SeatStatusOffered, to: domain.SeatStatusLocked, linked: true, allowed: true},
+		{from: domain.SeatStatusLocked, to: domain.SeatStatusAvailable, allowed: true},
+		{from: domain.SeatStatusLocked, to: domain.SeatStatusOffered, allowed: false},
+		{from: domain.SeatStatusLocked, to: domain.SeatStatusOffered, linked: true, allowed: true},
+		{from: domain.SeatStatusLocked, to: domain.SeatStatusSold, allowed: false},
+		{from: domain.SeatStatusLocked, to: domain.SeatStatusSold, linked: true, allowed: true},
+		{from: domain.SeatStatusSold, to: domain.SeatStatusAvailable, allowed: false},
+		{from: domain.SeatStatusSold, to: domain.SeatStatusAvailable, refund: true, allowed: true},
+		{from: domain.SeatStatusSold, to: domain.SeatStatusLocked, linked: true, allowed: false},
+		{from: domain.SeatStatusSold, to: domain.SeatStatusLocked, refund: true, allowed: false},
+		{from: domain.SeatStatusSold, to: domain.SeatStatusLocked, refund: true, linked: true, allowed: true},
+		{from: domain.SeatStatusSold, to: domain.SeatStatusOffered, refund: true, linked: true, allowed: false},
+	}
+	for _, tt := range tests {
+		err := domain.ValidateSeatStatusTransition("st1", tt.from, tt.to, tt.refund, tt.linked)
+		if tt.allowed {
+			suite.NoError(err, "%d to %d, refund %v, linked %v", tt.from, tt.to, tt.refund, tt.linked)
+			continue
+		}
+		var transitionErr *domain.SeatStatusTransitionError
+		if suite.ErrorAs(err, &transitionErr, "%d to %d, refund %v, linked %v", tt.from, tt.to, tt.refund, tt.linked) {
+			suite.Equal(&domain.SeatStatusTransitionError{SeatID: "st1", From: tt.from, To: tt.to}, transitionErr)
+		}
+		suite.ErrorIs(err, domain.ErrSeatConflict)
+	}
+}
+
+func (suite *SeatStatusTestSuite) TestSeatStorageErrors() {
+	_, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: idgen.New("st"), OrgID: suite.org.ID})
+	suite.ErrorIs(err, domain.ErrSeatNotFound)
+
+	_, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.seats[4].ID, idgen.New("st")},
+		StatusCode: domain.SeatStatusOffered,
+		UpdatedAt:  time.Now(),
+	})
+	suite.ErrorIs(err, domain.ErrSeatNotFound)
+
+	name := "duplicate"
+	err = suite.service.storage.CreateSeats(suite.ctx, suite.ids(), []*domain.NewSeat{
+		{ID: suite.seats[4].ID, SeatingPlanID: suite.spl.ID, Name: &name, Num: 1},
+	}, time.Now())
+	suite.ErrorIs(err, domain.ErrSeatConflict)
+
+	err = suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{suite.seats[5].ID}, time.Now())
+	suite.NoError(err)
+	_, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[5].ID, OrgID: suite.org.ID})
+	suite.ErrorIs(err, domain.ErrSeatDeleted)
+	num := int32(100)
+	err = suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: suite.seats[5].ID, Num: &num}}, time.Now())
+	var seatErr *domain.SeatError
+	suite.ErrorAs(err, &seatErr)
+	suite.ErrorIs(err, domain.ErrSeatDeleted)
+	suite.Equal([]string{suite.seats[5].ID}, seatErr.SeatIDs)
+}
+
+func (suite *SeatStatusTestSuite) TestUpdateSeatsStatusWithMissingSeat() {
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.seats[6].ID, idgen.New("st")},
+		StatusCode: domain.SeatStatusOffered,
+		UpdatedAt:  time.Now(),
+	})
+	suite.ErrorIs(err, domain.ErrSeatNotFound)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: suite.seats[6].ID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal(domain.SeatStatusAvailable, seat.StatusCode)
+}
+
+func (suite *SeatStatusTestSuite) TestUpdateSeatsStatusReturnsUpdatedSeats() {
+	orderID, orderItemID := idgen.New("ord"), idgen.New("oi")
+	seatIDs := []string{suite.seats[7].ID, suite.seats[8].ID}
+	change, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:        seatIDs,
+		StatusCode:     domain.SeatStatusSold,
+		AddOrderID:     &orderID,
+		AddOrderItemID: &orderItemID,
+		UpdatedAt:      time.Now(),
+	})
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, change.SeatIDs)
+
+	// seats of an unknown order item are not reported
+	change, err = suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		OrderItemIDs:  []string{orderItemID, idgen.New("oi")},
+		StatusCode:    domain.SeatStatusAvailable,
+		RemoveOrderID: true,
+		UpdatedAt:     time.Now(),
+	})
+	suite.NoError(err)
+	suite.ElementsMatch(seatIDs, change.SeatIDs)
+	suite.Len(change.PreviousStatuses, len(seatIDs))
+	for _, seatID := range seatIDs {
+		suite.Equal(domain.SeatStatusSold, change.PreviousStatuses[seatID])
+	}
+}
+
+func TestSeatStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatStatusTestSuite))
+}
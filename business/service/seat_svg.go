@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/proj/business/domain"
+)
+
+const (
+	defaultSeatMapSeatRadius = 10
+	seatMapUnknownColor      = "#cccccc"
+	seatMapBackgroundColor   = "#ffffff"
+	seatMapLabelColor        = "#212121"
+)
+
+var defaultSeatMapStatusColors = map[domain.SeatStatus]string{
+	domain.SeatStatusAvailable: "#4caf50",
+	domain.SeatStatusOffered:   "#ffc107",
+	domain.SeatStatusSold:      "#9e9e9e",
+	domain.SeatStatusLocked:    "#607d8b",
+}
+
+// seatMapPalette colors price categories without a configured color
+var seatMapPalette = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+// RenderSeatingPlanSVG writes an SVG document with not deleted seats of the seating plan ids.SplID. Seats are drawn by
+// their SvgPath and SvgTransform or as circles at X and Y when they have no path, and are labeled by OverrideNum or Num.
+func (s *Service) RenderSeatingPlanSVG(ctx context.Context, ids *domain.IDs, opts domain.SeatMapRenderOptions, w io.Writer) error {
+	seats, err := s.getSeatingPlanSeats(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := renderSeatMapSVG(w, seats, opts); err != nil {
+		return fmt.Errorf("error while rendering seating plan svg %w", err)
+	}
+	return nil
+}
+
+func renderSeatMapSVG(w io.Writer, seats []domain.Seat, opts domain.SeatMapRenderOptions) error {
+	radius := opts.SeatRadius
+	if radius <= 0 {
+		radius = defaultSeatMapSeatRadius
+	}
+	colorOf, err := seatMapColors(seats, opts)
+	if err != nil {
+		return err
+	}
+
+	// the view box contains all seat positions with a margin of one seat
+	var minX, minY, maxX, maxY int32
+	for i, seat := range seats {
+		if i == 0 || seat.X < minX {
+			minX = seat.X
+		}
+		if i == 0 || seat.Y < minY {
+			minY = seat.Y
+		}
+		if i == 0 || seat.X > maxX {
+			maxX = seat.X
+		}
+		if i == 0 || seat.Y > maxY {
+			maxY = seat.Y
+		}
+	}
+	minX, minY = minX-2*radius, minY-2*radius
+	width, height := maxX-minX+2*radius, maxY-minY+2*radius
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%d %d %d %d" width="%d" height="%d">`+"\n",
+		minX, minY, width, height, width, height)
+	fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", minX, minY, width, height, seatMapBackgroundColor)
+	fmt.Fprintf(bw, `<g font-family="sans-serif" font-size="%d" text-anchor="middle" dominant-baseline="central">`+"\n", radius)
+	for _, seat := range seats {
+		color := svgEscape(colorOf(seat))
+		if seat.SvgPath != "" {
+			fmt.Fprintf(bw, `<path id="%s" d="%s"`, svgEscape(seat.ID), svgEscape(seat.SvgPath))
+			if seat.SvgTransform != "" {
+				fmt.Fprintf(bw, ` transform="%s"`, svgEscape(seat.SvgTransform))
+			}
+			fmt.Fprintf(bw, ` fill="%s"/>`+"\n", color)
+		} else {
+			fmt.Fprintf(bw, `<circle id="%s" cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", svgEscape(seat.ID), seat.X, seat.Y, radius, color)
+		}
+		fmt.Fprintf(bw, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", seat.X, seat.Y, seatMapLabelColor, svgEscape(seatMapLabel(seat)))
+	}
+	bw.WriteString("</g>\n</svg>\n")
+	return bw.Flush()
+}
+
+// seatMapColors returns the function coloring seats according to the options
+func seatMapColors(seats []domain.Seat, opts domain.SeatMapRenderOptions) (func(domain.Seat) string, error) {
+	switch opts.ColorBy {
+	case "", domain.SeatMapColorByStatus:
+		colors := make(map[domain.SeatStatus]string, len(defaultSeatMapStatusColors))
+		for status, color := range defaultSeatMapStatusColors {
+			colors[status] = color
+		}
+		for status, color := range opts.StatusColors {
+			colors[status] = color
+		}
+		return func(seat domain.Seat) string {
+			status := seat.StatusCode
+			// seats without status are available
+			if status == 0 {
+				status = domain.SeatStatusAvailable
+			}
+			if color, ok := colors[status]; ok {
+				return color
+			}
+			return seatMapUnknownColor
+		}, nil
+	case domain.SeatMapColorByPriceCategory:
+		colors := make(map[string]string, len(opts.PriceCategoryColors))
+		for pcID, color := range opts.PriceCategoryColors {
+			colors[pcID] = color
+		}
+		var pcIDs []string
+		for _, seat := range seats {
+			if seat.PriceCategoryID == nil || *seat.PriceCategoryID == "" {
+				continue
+			}
+			if _, ok := colors[*seat.PriceCategoryID]; !ok {
+				colors[*seat.PriceCategoryID] = ""
+				pcIDs = append(pcIDs, *seat.PriceCategoryID)
+			}
+		}
+		// palette colors don't depend on the order of seats
+		sort.Strings(pcIDs)
+		for i, pcID := range pcIDs {
+			colors[pcID] = seatMapPalette[i%len(seatMapPalette)]
+		}
+		return func(seat domain.Seat) string {
+			if seat.PriceCategoryID == nil || *seat.PriceCategoryID == "" {
+				return seatMapUnknownColor
+			}
+			return colors[*seat.PriceCategoryID]
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown seat map color by %q", opts.ColorBy)
+}
+
+func seatMapLabel(seat domain.Seat) string {
+	if seat.OverrideNum != nil && *seat.OverrideNum != "" {
+		return *seat.OverrideNum
+	}
+	return strconv.Itoa(int(seat.Num))
+}
+
+func svgEscape(s string) string {
+	var sb strings.Builder
+	// writes to strings.Builder never fail
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
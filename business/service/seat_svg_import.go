@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/proj/business/domain"
+)
+
+// seatSVGAttributes map data attributes of seat shapes to columns of imported seats
+var seatSVGAttributes = map[string]string{
+	"data-name":           seatImportName,
+	"data-num":            seatImportNum,
+	"data-row":            seatImportRow,
+	"data-block":          seatImportBlock,
+	"data-gate":           seatImportGate,
+	"data-x":              seatImportX,
+	"data-y":              seatImportY,
+	"data-price-category": seatImportPriceCategory,
+	"data-wheelchair":     seatImportWheelchair,
+	"data-override-num":   seatImportOverrideNum,
+	"data-description":    seatImportDescription,
+}
+
+// seatSVGShapes converts supported shapes to SVG paths and returns their centers
+var seatSVGShapes = map[string]func(attrs map[string]string) (path string, x, y float64, err error){
+	"circle":   svgCirclePath,
+	"ellipse":  svgEllipsePath,
+	"rect":     svgRectPath,
+	"path":     svgPathPath,
+	"polygon":  svgPolygonPath,
+	"polyline": svgPolylinePath,
+}
+
+// ImportSeatsSVG creates seats of the seating plan ids.SplID from shapes of the SVG tagged with data-row and data-num
+// attributes, the other seat fields are read from data-name, data-block, data-gate, data-price-category,
+// data-wheelchair, data-override-num and data-description. The position defaults to the center of the shape unless
+// it's set by data-x and data-y, transforms of the shape and its groups aren't applied to it. All the other elements,
+// including the svg root and groups, are reported as unrecognized with the line their start tag starts on.
+// Nothing is created when any seat is invalid or when dryRun is set.
+func (s *Service) ImportSeatsSVG(ctx context.Context, ids *domain.IDs, r io.Reader, dryRun bool) (*domain.SeatImportResult, error) {
+	records, unrecognized, err := readSeatsSVG(r)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading seats svg %w", err)
+	}
+	res, err := s.importSeats(ctx, ids, records, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	res.Unrecognized = unrecognized
+	return res, nil
+}
+
+func readSeatsSVG(r io.Reader) ([]seatImportRecord, []domain.SeatImportLineError, error) {
+	decoder := xml.NewDecoder(r)
+	var records []seatImportRecord
+	var unrecognized []domain.SeatImportLineError
+	// transforms are the transform attributes of the open elements
+	var transforms []string
+	for {
+		// the decoder stops in front of the next token, so this is the line the start tag starts on
+		line, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return records, unrecognized, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		switch t := token.(type) {
+		case xml.EndElement:
+			transforms = transforms[:len(transforms)-1]
+		case xml.StartElement:
+			attrs := make(map[string]string, len(t.Attr))
+			for _, attr := range t.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+			transforms = append(transforms, strings.TrimSpace(attrs["transform"]))
+			toShape, isShape := seatSVGShapes[t.Name.Local]
+			_, hasRow := attrs["data-row"]
+			_, hasNum := attrs["data-num"]
+			switch {
+			case !hasRow && !hasNum && isShape:
+				unrecognized = append(unrecognized, domain.SeatImportLineError{Line: line, Message: fmt.Sprintf("<%s> isn't tagged as a seat", t.Name.Local)})
+				continue
+			case !hasRow && !hasNum:
+				unrecognized = append(unrecognized, domain.SeatImportLineError{Line: line, Message: fmt.Sprintf("<%s> isn't a seat", t.Name.Local)})
+				continue
+			case !isShape:
+				unrecognized = append(unrecognized, domain.SeatImportLineError{Line: line, Message: fmt.Sprintf("<%s> can't be a seat", t.Name.Local)})
+				continue
+			}
+
+			record := seatImportRecord{line: line, values: make(map[string]string)}
+			for attr, column := range seatSVGAttributes {
+				if v, ok := attrs[attr]; ok {
+					record.values[column] = strings.TrimSpace(v)
+				}
+			}
+			// the position of invalid shapes isn't reported, the shape error makes the seat invalid anyway
+			path, x, y, err := toShape(attrs)
+			if err != nil {
+				record.errs = append(record.errs, domain.SeatImportLineError{Line: line, Column: seatImportSvgPath, Message: err.Error()})
+			}
+			record.values[seatImportSvgPath] = path
+			if _, ok := record.values[seatImportX]; !ok {
+				record.values[seatImportX] = strconv.Itoa(int(math.Round(x)))
+			}
+			if _, ok := record.values[seatImportY]; !ok {
+				record.values[seatImportY] = strconv.Itoa(int(math.Round(y)))
+			}
+			record.values[seatImportSvgTransform] = svgTransform(transforms)
+			records = append(records, record)
+		}
+	}
+}
+
+// svgTransform joins transforms of the element and its ancestors, outer transforms go first
+func svgTransform(transforms []string) string {
+	var parts []string
+	for _, transform := range transforms {
+		if transform != "" {
+			parts = append(parts, transform)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// svgNumbers parses the attributes as numbers, missing attributes are zero
+func svgNumbers(attrs map[string]string, names ...string) ([]float64, error) {
+	numbers := make([]float64, len(names))
+	for i, name := range names {
+		v, ok := attrs[name]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "px"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, v)
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+func svgNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func svgCirclePath(attrs map[string]string) (string, float64, float64, error) {
+	n, err := svgNumbers(attrs, "cx", "cy", "r")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return svgEllipsePathOf(n[0], n[1], n[2], n[2])
+}
+
+func svgEllipsePath(attrs map[string]string) (string, float64, float64, error) {
+	n, err := svgNumbers(attrs, "cx", "cy", "rx", "ry")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return svgEllipsePathOf(n[0], n[1], n[2], n[3])
+}
+
+// svgEllipsePathOf draws the ellipse by two arcs
+func svgEllipsePathOf(cx, cy, rx, ry float64) (string, float64, float64, error) {
+	if rx <= 0 || ry <= 0 {
+		return "", 0, 0, errors.New("radius must be positive")
+	}
+	path := fmt.Sprintf("M%s %s a%s %s 0 1 0 %s 0 a%s %s 0 1 0 %s 0 Z",
+		svgNumber(cx-rx), svgNumber(cy), svgNumber(rx), svgNumber(ry), svgNumber(2*rx),
+		svgNumber(rx), svgNumber(ry), svgNumber(-2*rx))
+	return path, cx, cy, nil
+}
+
+func svgRectPath(attrs map[string]string) (string, float64, float64, error) {
+	n, err := svgNumbers(attrs, "x", "y", "width", "height")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	x, y, width, height := n[0], n[1], n[2], n[3]
+	if width <= 0 || height <= 0 {
+		return "", 0, 0, errors.New("size must be positive")
+	}
+	path := fmt.Sprintf("M%s %s h%s v%s h%s Z", svgNumber(x), svgNumber(y), svgNumber(width), svgNumber(height), svgNumber(-width))
+	return path, x + width/2, y + height/2, nil
+}
+
+// svgNumberPattern matches numbers of SVG attributes, they may be separated by a sign only, e.g. M120-195
+var svgNumberPattern = regexp.MustCompile(`[+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?`)
+
+// svgPathStart matches the moveto command a path starts with
+var svgPathStart = regexp.MustCompile(`^[Mm][\s,]*(` + svgNumberPattern.String() + `)[\s,]*(` + svgNumberPattern.String() + `)`)
+
+// svgPathPath keeps the path, its position is the starting point of the path
+func svgPathPath(attrs map[string]string) (string, float64, float64, error) {
+	d := strings.TrimSpace(attrs["d"])
+	m := svgPathStart.FindStringSubmatch(d)
+	if m == nil {
+		return "", 0, 0, errors.New("path must start with a moveto command")
+	}
+	x, errX := strconv.ParseFloat(m[1], 64)
+	y, errY := strconv.ParseFloat(m[2], 64)
+	if errX != nil || errY != nil {
+		return "", 0, 0, fmt.Errorf("invalid starting point %s %s", m[1], m[2])
+	}
+	return d, x, y, nil
+}
+
+func svgPolygonPath(attrs map[string]string) (string, float64, float64, error) {
+	return svgPointsPath(attrs["points"], true)
+}
+
+func svgPolylinePath(attrs map[string]string) (string, float64, float64, error) {
+	return svgPointsPath(attrs["points"], false)
+}
+
+// svgPointsPath draws lines through the points, its position is the center of their bounding box
+func svgPointsPath(points string, closed bool) (string, float64, float64, error) {
+	var n []float64
+	for _, v := range svgNumberPattern.FindAllString(points, -1) {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("invalid points %q", points)
+		}
+		n = append(n, f)
+	}
+	if len(n) < 4 || len(n)%2 != 0 {
+		return "", 0, 0, errors.New("points must be pairs of at least two points")
+	}
+	minX, minY, maxX, maxY := n[0], n[1], n[0], n[1]
+	parts := make([]string, 0, len(n)/2+1)
+	for i := 0; i < len(n); i += 2 {
+		command := "L"
+		if i == 0 {
+			command = "M"
+		}
+		parts = append(parts, fmt.Sprintf("%s%s %s", command, svgNumber(n[i]), svgNumber(n[i+1])))
+		minX, maxX = math.Min(minX, n[i]), math.Max(maxX, n[i])
+		minY, maxY = math.Min(minY, n[i+1]), math.Max(maxY, n[i+1])
+	}
+	if closed {
+		parts = append(parts, "Z")
+	}
+	return strings.Join(parts, " "), (minX + maxX) / 2, (minY + maxY) / 2, nil
+}
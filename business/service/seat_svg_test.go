@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type SeatSVGTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatSVGTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+	_, err := suite.service.storage.UpdateSeatsStatus(suite.ctx, suite.ids(), domain.UpdateSeatStatus{
+		SeatIDs:    []string{suite.seats[0].ID},
+		StatusCode: domain.SeatStatusSold,
+		UpdatedAt:  time.Now(),
+	})
+	suite.NoError(err)
+}
+
+func (suite *SeatSVGTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+// svgFills returns fill colors of seat shapes of the SVG by seat id together with texts of the labels
+func (suite *SeatSVGTestSuite) svgFills(svg []byte) (map[string]string, []string) {
+	fills := make(map[string]string)
+	var labels []string
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return fills, labels
+		}
+		suite.Require().NoError(err)
+		switch t := token.(type) {
+		case xml.StartElement:
+			inText = t.Name.Local == "text"
+			var id, fill string
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case "id":
+					id = attr.Value
+				case "fill":
+					fill = attr.Value
+				}
+			}
+			if id != "" {
+				fills[id] = fill
+			}
+		case xml.CharData:
+			if inText {
+				labels = append(labels, string(t))
+			}
+		case xml.EndElement:
+			inText = false
+		}
+	}
+}
+
+func (suite *SeatSVGTestSuite) TestRenderSeatingPlanSVGByStatus() {
+	var buf bytes.Buffer
+	err := suite.service.RenderSeatingPlanSVG(suite.ctx, suite.ids(), domain.SeatMapRenderOptions{
+		StatusColors: map[domain.SeatStatus]string{domain.SeatStatusSold: "red"},
+	}, &buf)
+	suite.NoError(err)
+	fills, labels := suite.svgFills(buf.Bytes())
+	suite.Len(fills, len(suite.seats))
+	suite.Len(labels, len(suite.seats))
+	suite.Equal("red", fills[suite.seats[0].ID])
+	suite.Equal(defaultSeatMapStatusColors[domain.SeatStatusAvailable], fills[suite.seats[1].ID])
+}
+
+func (suite *SeatSVGTestSuite) TestRenderSeatingPlanSVGByPriceCategory() {
+	var buf bytes.Buffer
+	err := suite.service.RenderSeatingPlanSVG(suite.ctx, suite.ids(), domain.SeatMapRenderOptions{ColorBy: domain.SeatMapColorByPriceCategory}, &buf)
+	suite.NoError(err)
+	fills, _ := suite.svgFills(buf.Bytes())
+	for _, seat := range suite.seats {
+		suite.Equal(seatMapPalette[0], fills[seat.ID])
+	}
+}
+
+func (suite *SeatSVGTestSuite) TestRenderSeatMapSVGLabelsAndEscapes() {
+	overrideNum := `1<A>`
+	seats := []domain.Seat{
+		{ID: "st1", Num: 1, X: 10, Y: 10, OverrideNum: &overrideNum},
+		{ID: "st2", Num: 2, X: 30, Y: 10, SvgPath: "M0 0 L10 0 L10 10 Z", SvgTransform: "translate(25 5)"},
+	}
+	var buf bytes.Buffer
+	err := renderSeatMapSVG(&buf, seats, domain.SeatMapRenderOptions{})
+	suite.NoError(err)
+	fills, labels := suite.svgFills(buf.Bytes())
+	suite.Len(fills, 2)
+	suite.Equal([]string{"1<A>", "2"}, labels)
+
+	err = renderSeatMapSVG(&buf, seats, domain.SeatMapRenderOptions{ColorBy: "row"})
+	suite.Error(err)
+}
+
+func (suite *SeatSVGTestSuite) TestImportSeatsSVG() {
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg">
+<rect x="0" y="0" width="500" height="20"/>
+<g transform="translate(10 20)">
+  <circle cx="100" cy="200" r="5" data-row="%[1]s" data-num="301" data-name="C301"/>
+  <path d="M120-195 l10 0 l0 10 z" transform="rotate(10)" data-row="%[1]s" data-num="302"/>
+  <text data-num="303">303</text>
+  <polygon points="200,200 210,200 210,210 200,210"
+           data-row="%[1]s" data-num="304"/>
+  <polyline
+    points="220 200 230 200 230 210" data-row="%[1]s" data-num="305"/>
+</g>
+</svg>`, *suite.seats[0].SeatRowID)
+
+	res, err := suite.service.ImportSeatsSVG(suite.ctx, suite.ids(), strings.NewReader(svg), true)
+	suite.NoError(err)
+	suite.Empty(res.Errors)
+	suite.Empty(res.SeatIDs)
+	suite.Equal([]domain.SeatImportLineError{
+		{Line: 1, Message: "<svg> isn't a seat"},
+		{Line: 2, Message: "<rect> isn't tagged as a seat"},
+		{Line: 3, Message: "<g> isn't a seat"},
+		{Line: 6, Message: "<text> can't be a seat"},
+	}, res.Unrecognized)
+
+	res, err = suite.service.ImportSeatsSVG(suite.ctx, suite.ids(), strings.NewReader(svg), false)
+	suite.NoError(err)
+	suite.Len(res.SeatIDs, 4)
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: res.SeatIDs[0], OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal("C301", seat.Name)
+	suite.Equal(int32(301), seat.Num)
+	suite.Equal(int32(100), seat.X)
+	suite.Equal(int32(200), seat.Y)
+	suite.Equal("M95 200 a5 5 0 1 0 10 0 a5 5 0 1 0 -10 0 Z", seat.SvgPath)
+	suite.Equal("translate(10 20)", seat.SvgTransform)
+	seat, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: res.SeatIDs[1], OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal("translate(10 20) rotate(10)", seat.SvgTransform)
+	suite.Equal(int32(120), seat.X)
+	suite.Equal(int32(-195), seat.Y)
+	seat, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: res.SeatIDs[2], OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal("M200 200 L210 200 L210 210 L200 210 Z", seat.SvgPath)
+	suite.Equal(int32(205), seat.X)
+	suite.Equal(int32(205), seat.Y)
+	seat, err = suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: res.SeatIDs[3], OrgID: suite.org.ID})
+	suite.NoError(err)
+	suite.Equal("M220 200 L230 200 L230 210", seat.SvgPath)
+}
+
+func (suite *SeatSVGTestSuite) TestReadSeatsSVGReportsLineOfStartTag() {
+	svg := "<svg>\n<circle\n  cx=\"1\"\n  cy=\"1\" r=\"1\"\n  data-num=\"1\"/>\n<ellipse\n  cx=\"1\"/>\n</svg>"
+	records, unrecognized, err := readSeatsSVG(strings.NewReader(svg))
+	suite.NoError(err)
+	if suite.Len(records, 1) {
+		suite.Equal(2, records[0].line)
+	}
+	suite.Equal([]domain.SeatImportLineError{
+		{Line: 1, Message: "<svg> isn't a seat"},
+		{Line: 6, Message: "<ellipse> isn't tagged as a seat"},
+	}, unrecognized)
+}
+
+func (suite *SeatSVGTestSuite) TestImportSeatsSVGReportsInvalidShapes() {
+	svg := `<svg><circle cx="10" cy="10" r="0" data-row="unknown" data-num="1"/></svg>`
+	res, err := suite.service.ImportSeatsSVG(suite.ctx, suite.ids(), strings.NewReader(svg), true)
+	suite.NoError(err)
+	suite.Equal([]domain.SeatImportLineError{
+		{Line: 1, Column: "svg_path", Message: "radius must be positive"},
+		{Line: 1, Column: "row", Message: `unknown row "unknown"`},
+	}, res.Errors)
+
+	_, err = suite.service.ImportSeatsSVG(suite.ctx, suite.ids(), strings.NewReader("<svg><circle></svg>"), true)
+	suite.Error(err)
+}
+
+func TestSeatSVGTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatSVGTestSuite))
+}
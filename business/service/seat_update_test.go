@@ -0,0 +1,89 @@
+package service
+
+import (
+	"github.com/proj/business/domain"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type SeatUpdateTestSuite struct {
+	CommonSuite
+	seats []domain.Seat
+}
+
+func (suite *SeatUpdateTestSuite) SetupSuite() {
+	suite.seats = suite.SetupOrgWithSeats(12, 6)
+}
+
+func (suite *SeatUpdateTestSuite) TearDownSuite() {
+	defer suite.testUtil.Teardown()
+}
+
+func (suite *SeatUpdateTestSuite) getSeat(seatID string) *domain.Seat {
+	seat, err := suite.service.storage.GetSeatByID(suite.ctx, &domain.IDs{ID: seatID, OrgID: suite.org.ID})
+	suite.NoError(err)
+	return seat
+}
+
+func (suite *SeatUpdateTestSuite) TestUpdateSeatsIfUnmodified() {
+	name := "A1"
+	err := suite.service.storage.UpdateSeatsIfUnmodified(suite.ctx, suite.ids(), []*domain.VersionedUpdateSeat{
+		{UpdateSeat: &domain.UpdateSeat{ID: suite.seats[0].ID, Name: &name}},
+	}, time.Now())
+	suite.NoError(err)
+	seat := suite.getSeat(suite.seats[0].ID)
+	suite.Equal(name, seat.Name)
+	suite.NotNil(seat.UpdatedAt)
+
+	// the modification time read by the caller guards the next update
+	name = "A2"
+	err = suite.service.storage.UpdateSeatsIfUnmodified(suite.ctx, suite.ids(), []*domain.VersionedUpdateSeat{
+		{UpdateSeat: &domain.UpdateSeat{ID: suite.seats[0].ID, Name: &name}, ExpectedUpdatedAt: seat.UpdatedAt},
+	}, time.Now())
+	suite.NoError(err)
+	suite.Equal(name, suite.getSeat(suite.seats[0].ID).Name)
+}
+
+func (suite *SeatUpdateTestSuite) TestUpdateSeatsIfUnmodifiedRejectsWholeBatch() {
+	unmodifiedSeat := suite.getSeat(suite.seats[2].ID)
+	modifiedSeat := suite.getSeat(suite.seats[3].ID)
+	deletedSeat := suite.getSeat(suite.seats[4].ID)
+
+	concurrentName := "concurrent"
+	err := suite.service.storage.UpdateSeats(suite.ctx, suite.ids(), []*domain.UpdateSeat{{ID: modifiedSeat.ID, Name: &concurrentName}}, time.Now())
+	suite.NoError(err)
+	err = suite.service.storage.DeleteSeats(suite.ctx, suite.ids(), []string{deletedSeat.ID}, time.Now())
+	suite.NoError(err)
+
+	name := "B1"
+	err = suite.service.storage.UpdateSeatsIfUnmodified(suite.ctx, suite.ids(), []*domain.VersionedUpdateSeat{
+		{UpdateSeat: &domain.UpdateSeat{ID: unmodifiedSeat.ID, Name: &name}, ExpectedUpdatedAt: unmodifiedSeat.UpdatedAt},
+		{UpdateSeat: &domain.UpdateSeat{ID: modifiedSeat.ID, Name: &name}, ExpectedUpdatedAt: modifiedSeat.UpdatedAt},
+		{UpdateSeat: &domain.UpdateSeat{ID: deletedSeat.ID, Name: &name}, ExpectedUpdatedAt: deletedSeat.UpdatedAt},
+	}, time.Now())
+	suite.ErrorIs(err, domain.ErrSeatConflict)
+	var modificationErr *domain.SeatConcurrentModificationError
+	suite.ErrorAs(err, &modificationErr)
+	suite.ElementsMatch([]string{modifiedSeat.ID, deletedSeat.ID}, modificationErr.SeatIDs)
+
+	// none of the seats of the batch is updated
+	suite.Equal(unmodifiedSeat.Name, suite.getSeat(unmodifiedSeat.ID).Name)
+	suite.Equal(concurrentName, suite.getSeat(modifiedSeat.ID).Name)
+}
+
+func (suite *SeatUpdateTestSuite) TestUpdateSeatsIfUnmodifiedRejectsMissingSeat() {
+	name := "C1"
+	err := suite.service.storage.UpdateSeatsIfUnmodified(suite.ctx, suite.ids(), []*domain.VersionedUpdateSeat{
+		{UpdateSeat: &domain.UpdateSeat{ID: suite.seats[5].ID, Name: &name}},
+		{UpdateSeat: &domain.UpdateSeat{ID: "missing", Name: &name}},
+	}, time.Now())
+	var modificationErr *domain.SeatConcurrentModificationError
+	suite.ErrorAs(err, &modificationErr)
+	suite.Equal([]string{"missing"}, modificationErr.SeatIDs)
+	suite.NotEqual(name, suite.getSeat(suite.seats[5].ID).Name)
+}
+
+func TestSeatUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(SeatUpdateTestSuite))
+}
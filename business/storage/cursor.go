@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proj/business/domain"
+)
+
+const cursorSeparator = "\x1f"
+
+// encodeCursor returns an opaque cursor for the key values of the last returned row
+func encodeCursor(keys ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(keys, cursorSeparator)))
+}
+
+// decodeCursor returns the key values of the cursor, it expects exactly n values
+func decodeCursor(cursor string, n int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	keys := strings.Split(string(raw), cursorSeparator)
+	if len(keys) != n {
+		return nil, domain.ErrInvalidCursor
+	}
+	return keys, nil
+}
+
+func encodeCursorTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func decodeCursorTime(s string) (time.Time, error) {
+	nsec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidCursor
+	}
+	return time.Unix(0, nsec).UTC(), nil
+}
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS seat_holds
+(
+    id              VARCHAR(64) PRIMARY KEY,
+    token           VARCHAR(64) NOT NULL,
+    org_id          VARCHAR(64) NOT NULL,
+    seating_plan_id VARCHAR(64) NOT NULL,
+    seat_id         VARCHAR(64) NOT NULL REFERENCES seats (id),
+    expires_at      TIMESTAMP   NOT NULL,
+    created_at      TIMESTAMP   NOT NULL,
+    created_by_id   VARCHAR(64) NOT NULL
+);
+
+-- a seat can be held by a single token at a time
+CREATE UNIQUE INDEX IF NOT EXISTS seat_holds_seat_id_idx ON seat_holds (seat_id);
+CREATE INDEX IF NOT EXISTS seat_holds_org_id_token_idx ON seat_holds (org_id, token);
+CREATE INDEX IF NOT EXISTS seat_holds_expires_at_idx ON seat_holds (expires_at);
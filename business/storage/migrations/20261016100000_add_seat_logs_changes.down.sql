@@ -0,0 +1 @@
+ALTER TABLE seat_logs DROP COLUMN IF EXISTS changes;
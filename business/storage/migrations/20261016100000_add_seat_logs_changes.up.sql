@@ -0,0 +1 @@
+ALTER TABLE seat_logs ADD COLUMN IF NOT EXISTS changes JSONB;
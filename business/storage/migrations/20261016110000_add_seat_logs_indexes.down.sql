@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS seat_logs_seat_id_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_seating_plan_id_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_order_id_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_created_at_idx;
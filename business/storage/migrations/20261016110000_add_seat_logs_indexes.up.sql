@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS seat_logs_seat_id_created_at_idx ON seat_logs (seat_id, created_at, id);
+CREATE INDEX IF NOT EXISTS seat_logs_seating_plan_id_created_at_idx ON seat_logs (seating_plan_id, created_at, id);
+CREATE INDEX IF NOT EXISTS seat_logs_order_id_created_at_idx ON seat_logs (order_id, created_at, id);
+CREATE INDEX IF NOT EXISTS seat_logs_created_at_idx ON seat_logs (created_at, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS seats_seating_plan_id_name_idx;
+DROP INDEX IF EXISTS seats_seating_plan_id_num_idx;
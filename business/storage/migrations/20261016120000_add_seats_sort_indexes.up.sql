@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS seats_seating_plan_id_name_idx ON seats (seating_plan_id, COALESCE(name, ''), id);
+CREATE INDEX IF NOT EXISTS seats_seating_plan_id_num_idx ON seats (seating_plan_id, COALESCE(num, 0), id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS seats_seating_plan_id_status_code_idx;
+DROP INDEX IF EXISTS seats_seat_row_id_idx;
+DROP INDEX IF EXISTS seats_order_id_idx;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS seats_seating_plan_id_status_code_idx ON seats (seating_plan_id, status_code);
+CREATE INDEX IF NOT EXISTS seats_seat_row_id_idx ON seats (seat_row_id);
+CREATE INDEX IF NOT EXISTS seats_order_id_idx ON seats (order_id);
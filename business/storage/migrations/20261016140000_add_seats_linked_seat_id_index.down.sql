@@ -0,0 +1 @@
+DROP INDEX IF EXISTS seats_linked_seat_id_idx;
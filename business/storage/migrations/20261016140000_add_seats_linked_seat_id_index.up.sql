@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS seats_linked_seat_id_idx ON seats (linked_seat_id) WHERE linked_seat_id IS NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS seat_accessibility_rules
+(
+    org_id                          VARCHAR PRIMARY KEY,
+    accessibility_price_name_ids    VARCHAR[] NOT NULL DEFAULT '{}',
+    require_companion               BOOLEAN   NOT NULL DEFAULT false,
+    release_before_event_minutes    INTEGER   NOT NULL DEFAULT 0,
+    created_at                      TIMESTAMP NOT NULL,
+    created_by_id                   VARCHAR   NOT NULL,
+    updated_at                      TIMESTAMP,
+    updated_by_id                   VARCHAR
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS seat_scoring_configs
+(
+    seating_plan_id     VARCHAR PRIMARY KEY,
+    org_id              VARCHAR          NOT NULL,
+    distance_weight     DOUBLE PRECISION NOT NULL DEFAULT 1,
+    centrality_weight   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    aisle_weight        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    row_weight          DOUBLE PRECISION NOT NULL DEFAULT 0,
+    row_preferences     JSONB            NOT NULL DEFAULT '{}',
+    block_weight        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    block_biases        JSONB            NOT NULL DEFAULT '{}',
+    created_at          TIMESTAMP        NOT NULL,
+    created_by_id       VARCHAR          NOT NULL,
+    updated_at          TIMESTAMP,
+    updated_by_id       VARCHAR
+);
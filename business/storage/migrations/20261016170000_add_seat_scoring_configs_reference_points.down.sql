@@ -0,0 +1 @@
+ALTER TABLE seat_scoring_configs DROP COLUMN IF EXISTS reference_points;
@@ -0,0 +1 @@
+ALTER TABLE seat_scoring_configs ADD COLUMN IF NOT EXISTS reference_points JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS seat_logs_org_id_created_at_idx;
+ALTER TABLE seat_logs DROP COLUMN IF EXISTS org_id;
@@ -0,0 +1,9 @@
+ALTER TABLE seat_logs ADD COLUMN IF NOT EXISTS org_id VARCHAR(64);
+
+-- logs of seats purged before the migration are left without org
+UPDATE seat_logs sl
+SET org_id = s.org_id
+FROM seats s
+WHERE s.id = sl.seat_id AND sl.org_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS seat_logs_org_id_created_at_idx ON seat_logs (org_id, created_at, id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS seat_logs_seat_id_created_at_seq_idx;
+DROP INDEX IF EXISTS seat_logs_seating_plan_id_created_at_seq_idx;
+DROP INDEX IF EXISTS seat_logs_order_id_created_at_seq_idx;
+DROP INDEX IF EXISTS seat_logs_org_id_created_at_seq_idx;
+CREATE INDEX IF NOT EXISTS seat_logs_seat_id_created_at_idx ON seat_logs (seat_id, created_at, id);
+CREATE INDEX IF NOT EXISTS seat_logs_seating_plan_id_created_at_idx ON seat_logs (seating_plan_id, created_at, id);
+CREATE INDEX IF NOT EXISTS seat_logs_order_id_created_at_idx ON seat_logs (order_id, created_at, id);
+CREATE INDEX IF NOT EXISTS seat_logs_org_id_created_at_idx ON seat_logs (org_id, created_at, id);
+ALTER TABLE seat_logs DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,12 @@
+-- seq orders logs written at the same time in the order they were inserted
+ALTER TABLE seat_logs ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+
+DROP INDEX IF EXISTS seat_logs_seat_id_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_seating_plan_id_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_order_id_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_created_at_idx;
+DROP INDEX IF EXISTS seat_logs_org_id_created_at_idx;
+CREATE INDEX IF NOT EXISTS seat_logs_seat_id_created_at_seq_idx ON seat_logs (seat_id, created_at, seq);
+CREATE INDEX IF NOT EXISTS seat_logs_seating_plan_id_created_at_seq_idx ON seat_logs (seating_plan_id, created_at, seq);
+CREATE INDEX IF NOT EXISTS seat_logs_order_id_created_at_seq_idx ON seat_logs (order_id, created_at, seq);
+CREATE INDEX IF NOT EXISTS seat_logs_org_id_created_at_seq_idx ON seat_logs (org_id, created_at, seq);
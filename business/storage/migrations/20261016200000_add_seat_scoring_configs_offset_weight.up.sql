@@ -0,0 +1 @@
+ALTER TABLE seat_scoring_configs ADD COLUMN IF NOT EXISTS offset_weight DOUBLE PRECISION NOT NULL DEFAULT 1;
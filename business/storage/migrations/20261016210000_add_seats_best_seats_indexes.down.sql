@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS seats_seating_plan_id_price_category_id_status_code_idx;
+DROP INDEX IF EXISTS seats_seat_block_id_idx;
@@ -0,0 +1,4 @@
+-- ListSeatsByDistance filters seats of the seating plan by price category and status before ordering them by distance
+CREATE INDEX IF NOT EXISTS seats_seating_plan_id_price_category_id_status_code_idx ON seats (seating_plan_id, price_category_id, status_code);
+-- best seats load the seating plan block by block
+CREATE INDEX IF NOT EXISTS seats_seat_block_id_idx ON seats (seat_block_id);
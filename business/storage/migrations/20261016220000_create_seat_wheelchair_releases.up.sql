@@ -0,0 +1,12 @@
+-- wheelchair spaces released to general sale for an event, the seats keep their wheelchair flag
+CREATE TABLE IF NOT EXISTS seat_wheelchair_releases
+(
+    seat_id         VARCHAR(64) NOT NULL,
+    event_id        VARCHAR(64) NOT NULL,
+    org_id          VARCHAR(64) NOT NULL,
+    seating_plan_id VARCHAR(64) NOT NULL,
+    released_at     TIMESTAMP   NOT NULL,
+    PRIMARY KEY (seat_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS seat_wheelchair_releases_event_id_idx ON seat_wheelchair_releases (event_id);
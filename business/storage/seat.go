@@ -2,7 +2,12 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -21,33 +26,42 @@ func (s *Storage) CreateSeats(ctx context.Context, ids *domain.IDs, seats []*dom
 	defer span.End()
 
 	err := s.execTx(ctx, func(tx *Queries) error {
-		for _, seat := range seats {
-			ids.ID = seat.ID
-			insertSeatParams := seatToCreateParams(ids, seat, t)
-			if _, err := tx.InsertSeat(ctx, insertSeatParams); err != nil {
-				return err
-			}
+		return createSeats(ctx, tx, ids, seats, "created", t)
+	})
 
-			seatLogParams := CreateSeatLogParams{
-				ID:            idgen.New("sl"),
-				SeatID:        seat.ID,
-				UserID:        nullString(ids.UserID),
-				SeatingPlanID: nullString(seat.SeatingPlanID),
-				Action:        nullString("created"),
-				CreatedAt:     t,
-			}
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("create seats tx: %w", err)
+	}
 
-			if _, err := tx.CreateSeatLog(ctx, seatLogParams); err != nil {
-				return err
+	return nil
+}
+
+// createSeats inserts the seats and logs their creation with the given action
+func createSeats(ctx context.Context, tx *Queries, ids *domain.IDs, seats []*domain.NewSeat, action string, t time.Time) error {
+	for _, seat := range seats {
+		ids.ID = seat.ID
+		insertSeatParams := seatToCreateParams(ids, seat, t)
+		if _, err := tx.InsertSeat(ctx, insertSeatParams); err != nil {
+			if isUniqueViolation(err) {
+				return &domain.SeatError{OrigError: domain.ErrSeatConflict, SeatIDs: []string{seat.ID}}
 			}
+			return err
 		}
 
-		return nil
-	})
+		seatLogParams := InsertSeatLogParams{
+			ID:            idgen.New("sl"),
+			OrgID:         ids.OrgID,
+			SeatID:        seat.ID,
+			UserID:        nullString(ids.UserID),
+			SeatingPlanID: nullString(insertSeatParams.SeatingPlanID),
+			Action:        nullString(action),
+			CreatedAt:     t,
+		}
 
-	if err != nil {
-		span.RecordError(err)
-		return fmt.Errorf("create seats tx: %w", err)
+		if err := createSeatLog(ctx, tx, seatLogParams, seatCreateChanges(insertSeatParams)); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -57,9 +71,15 @@ func (s *Storage) CreateSeats(ctx context.Context, ids *domain.IDs, seats []*dom
 func (s *Storage) GetSeatByID(ctx context.Context, ids *domain.IDs) (*domain.Seat, error) {
 	getSeatParams := GetSeatByIDParams{ID: ids.ID, OrgID: ids.OrgID}
 	row, err := s.queries.GetSeatByID(ctx, getSeatParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = &domain.SeatError{OrigError: domain.ErrSeatNotFound, SeatIDs: []string{ids.ID}}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("query seat: %w", err)
 	}
+	if row.DeletedAt.Valid {
+		return nil, fmt.Errorf("query seat: %w", &domain.SeatError{OrigError: domain.ErrSeatDeleted, SeatIDs: []string{ids.ID}})
+	}
 	seat := convertToDomainSeats(row)
 	return &seat, nil
 }
@@ -73,7 +93,9 @@ func (s *Storage) UpdateSeats(ctx context.Context, ids *domain.IDs, seats []*dom
 	)
 	defer span.End()
 
-	err := updateSeats(ctx, s.queries, seats, ids, t)
+	err := s.execTx(ctx, func(tx *Queries) error {
+		return updateSeats(ctx, tx, seats, ids, t)
+	})
 	if err != nil {
 		span.RecordError(err)
 		return fmt.Errorf("update seats tx: %w", err)
@@ -82,30 +104,156 @@ func (s *Storage) UpdateSeats(ctx context.Context, ids *domain.IDs, seats []*dom
 	return nil
 }
 
+// UpdateSeatsIfUnmodified updates seats only if none of them was modified after the caller has read it.
+// The whole batch is rejected with domain.SeatConcurrentModificationError listing the modified seats otherwise.
+func (s *Storage) UpdateSeatsIfUnmodified(ctx context.Context, ids *domain.IDs, seats []*domain.VersionedUpdateSeat, t time.Time) error {
+	ctx, span := s.tracer.Start(ctx, "storage.UpdateSeatsIfUnmodified")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("seats").String(spew.Sdump(seats)),
+	)
+	defer span.End()
+
+	err := s.execTx(ctx, func(tx *Queries) error {
+		seatIDs := make([]string, len(seats))
+		updates := make([]*domain.UpdateSeat, len(seats))
+		for i, seat := range seats {
+			seatIDs[i] = seat.ID
+			updates[i] = seat.UpdateSeat
+		}
+		lockedSeats, err := tx.LockSeatsForUpdate(ctx, LockSeatsForUpdateParams{OrgID: ids.OrgID, SeatIds: seatIDs})
+		if err != nil {
+			return err
+		}
+		if modifiedSeatIDs := concurrentlyModifiedSeats(seats, lockedSeats); len(modifiedSeatIDs) > 0 {
+			return &domain.SeatConcurrentModificationError{
+				OrigError: errors.New("seats were modified concurrently"),
+				SeatIDs:   modifiedSeatIDs,
+			}
+		}
+		return updateSeats(ctx, tx, updates, ids, t)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("update seats if unmodified tx: %w", err)
+	}
+
+	return nil
+}
+
+// concurrentlyModifiedSeats returns ids of seats which were updated, deleted or are missing
+// compared to the state expected by the caller
+func concurrentlyModifiedSeats(seats []*domain.VersionedUpdateSeat, lockedSeats []LockSeatsForUpdateRow) []string {
+	lockedSeatsByID := make(map[string]LockSeatsForUpdateRow, len(lockedSeats))
+	for _, ls := range lockedSeats {
+		lockedSeatsByID[ls.ID] = ls
+	}
+	var modifiedSeatIDs []string
+	for _, seat := range seats {
+		ls, ok := lockedSeatsByID[seat.ID]
+		switch {
+		case !ok || ls.DeletedAt.Valid:
+			modifiedSeatIDs = append(modifiedSeatIDs, seat.ID)
+		case seat.ExpectedUpdatedAt == nil && ls.UpdatedAt.Valid:
+			modifiedSeatIDs = append(modifiedSeatIDs, seat.ID)
+		case seat.ExpectedUpdatedAt != nil && (!ls.UpdatedAt.Valid || !ls.UpdatedAt.Time.Equal(*seat.ExpectedUpdatedAt)):
+			modifiedSeatIDs = append(modifiedSeatIDs, seat.ID)
+		}
+	}
+	return modifiedSeatIDs
+}
+
 func updateSeats(ctx context.Context, tx *Queries, seats []*domain.UpdateSeat, ids *domain.IDs, t time.Time) error {
+	seatIDs := make([]string, len(seats))
+	for i, seat := range seats {
+		seatIDs[i] = seat.ID
+	}
+	// the locked rows are the current state of the seats for the transition checks and the logged changes
+	currentSeats, err := lockSeats(ctx, tx, ids.OrgID, seatIDs)
+	if err != nil {
+		return err
+	}
 	for _, seat := range seats {
 		updateSeatParams := seatToUpdateParams(ids, seat, t)
+		currentSeat := currentSeats[seat.ID]
+		if updateSeatParams.SetStatusCode {
+			currentStatus := domain.SeatStatusAvailable
+			if currentSeat.StatusCode.Int32 != 0 {
+				currentStatus = domain.SeatStatus(currentSeat.StatusCode.Int32)
+			}
+			newStatus := domain.SeatStatus(updateSeatParams.StatusCode)
+			if err := domain.ValidateSeatStatusTransition(seat.ID, currentStatus, newStatus, false, currentSeat.LinkedSeatID.String != ""); err != nil {
+				return err
+			}
+			// the same seat may be updated again later in the batch
+			currentSeat.StatusCode = sql.NullInt32{Int32: int32(newStatus), Valid: true}
+			currentSeats[seat.ID] = currentSeat
+		}
 		if err := tx.UpdateSeat(ctx, updateSeatParams); err != nil {
 			return err
 		}
 
-		seatLogParams := CreateSeatLogParams{
-			ID:        idgen.New("sl"),
-			SeatID:    seat.ID,
-			UserID:    nullString(ids.UserID),
-			Action:    nullString("updated"),
-			CreatedAt: t,
+		seatLogParams := InsertSeatLogParams{
+			ID:            idgen.New("sl"),
+			OrgID:         ids.OrgID,
+			SeatID:        seat.ID,
+			UserID:        nullString(ids.UserID),
+			SeatingPlanID: nullString(currentSeat.SeatingPlanID),
+			OrderID:       currentSeat.OrderID,
+			Action:        nullString("updated"),
+			CreatedAt:     t,
 		}
-		if _, err := tx.CreateSeatLog(ctx, seatLogParams); err != nil {
+		if updateSeatParams.SetOrderID {
+			seatLogParams.OrderID = nullString(updateSeatParams.OrderID)
+		}
+		if err := createSeatLog(ctx, tx, seatLogParams, seatUpdateChanges(currentSeat, updateSeatParams)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func updateSeatsStatus(ctx context.Context, tx *Queries, ids domain.IDs, status domain.UpdateSeatStatus) error {
+// UpdateSeatsStatus sets the status of seats and of seats linked to them and attaches or detaches their order,
+// it returns ids of affected seats with their previous statuses
+func (s *Storage) UpdateSeatsStatus(ctx context.Context, ids *domain.IDs, status domain.UpdateSeatStatus) (*domain.SeatsStatusChange, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.UpdateSeatsStatus")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("status").String(spew.Sdump(status)),
+	)
+	defer span.End()
+
+	var change *domain.SeatsStatusChange
+	err := s.execTx(ctx, func(tx *Queries) error {
+		var err error
+		change, err = updateSeatsStatus(ctx, tx, *ids, status)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("update seats status tx: %w", err)
+	}
+
+	return change, nil
+}
+
+func updateSeatsStatus(ctx context.Context, tx *Queries, ids domain.IDs, status domain.UpdateSeatStatus) (*domain.SeatsStatusChange, error) {
 	var sIDs []string
 	var err error
+	// seats linked to the seats change their status as well
+	status.SeatIDs, err = withLinkedSeats(ctx, tx, ids.OrgID, status.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+	var lockOrderItemIDs []string
+	if len(status.SeatIDs) < 1 {
+		lockOrderItemIDs = status.OrderItemIDs
+	}
+	// seats can be moved back to available from sold only when their order is removed
+	lockedSeats, err := validateSeatsStatusTransition(ctx, tx, ids.OrgID, status.SeatIDs, lockOrderItemIDs, status.StatusCode, status.RemoveOrderID)
+	if err != nil {
+		return nil, err
+	}
 	upArgs := UpdateSeatStatusByIDsParams{
 		OrderItemIds: status.OrderItemIDs,
 		SeatIds:      status.SeatIDs,
@@ -116,7 +264,7 @@ func updateSeatsStatus(ctx context.Context, tx *Queries, ids domain.IDs, status
 	}
 	sIDs, err = tx.UpdateSeatStatusByIDs(ctx, upArgs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if status.AddOrderID != nil {
 		upsoArgs := AddOrderToSeatsParams{
@@ -129,7 +277,7 @@ func updateSeatsStatus(ctx context.Context, tx *Queries, ids domain.IDs, status
 		}
 		err = tx.AddOrderToSeats(ctx, upsoArgs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	if status.RemoveOrderID {
@@ -144,13 +292,36 @@ func updateSeatsStatus(ctx context.Context, tx *Queries, ids domain.IDs, status
 		}
 		err = tx.RemoveOrderFromSeats(ctx, upsoArgs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	cSeatIDs := status.SeatIDs
-	if len(status.SeatIDs) < 1 {
-		cSeatIDs = sIDs
+	// linked seats are not sold on their own, so they are locked again instead of becoming available
+	relockedSeatIDs := make(map[string]bool)
+	if status.StatusCode == domain.SeatStatusAvailable {
+		if seatIDs := linkedSeatIDs(lockedSeats); len(seatIDs) > 0 {
+			for _, seatID := range seatIDs {
+				err := domain.ValidateSeatStatusTransition(seatID, lockedSeatStatus(lockedSeats[seatID]), domain.SeatStatusLocked, status.RemoveOrderID, true)
+				if err != nil {
+					return nil, err
+				}
+			}
+			relockArgs := UpdateSeatStatusByIDsParams{
+				SeatIds:     seatIDs,
+				OrgID:       ids.OrgID,
+				UpdatedAt:   nullTime(status.UpdatedAt),
+				UpdatedByID: nullString(ids.UserID),
+				StatusCode:  int32(domain.SeatStatusLocked),
+			}
+			if _, err := tx.UpdateSeatStatusByIDs(ctx, relockArgs); err != nil {
+				return nil, err
+			}
+			for _, seatID := range seatIDs {
+				relockedSeatIDs[seatID] = true
+			}
+		}
 	}
+	// only the seats actually updated are reported
+	cSeatIDs := sIDs
 
 	actionMsg := fmt.Sprintf("status set to %d", status.StatusCode)
 	if status.RemoveOrderID {
@@ -159,19 +330,32 @@ func updateSeatsStatus(ctx context.Context, tx *Queries, ids domain.IDs, status
 	if status.RemoveSplitOrderID {
 		actionMsg += ", split order removed"
 	}
+	previousStatuses := make(map[string]domain.SeatStatus, len(cSeatIDs))
 	for _, seatID := range cSeatIDs {
-		seatLogParams := CreateSeatLogParams{
-			ID:        idgen.New("sl"),
-			SeatID:    seatID,
-			UserID:    nullString(ids.UserID),
-			Action:    nullString(actionMsg),
-			CreatedAt: status.UpdatedAt,
-		}
-		if _, err := tx.CreateSeatLog(ctx, seatLogParams); err != nil {
-			return err
+		lockedSeat := lockedSeats[seatID]
+		previousStatuses[seatID] = lockedSeatStatus(lockedSeat)
+		seatLogParams := InsertSeatLogParams{
+			ID:            idgen.New("sl"),
+			OrgID:         ids.OrgID,
+			SeatID:        seatID,
+			UserID:        nullString(ids.UserID),
+			SeatingPlanID: nullString(lockedSeat.SeatingPlanID),
+			OrderID:       lockedSeat.OrderID,
+			Action:        nullString(actionMsg),
+			CreatedAt:     status.UpdatedAt,
+		}
+		if status.AddOrderID != nil {
+			seatLogParams.OrderID = nullString(*status.AddOrderID)
+		}
+		seatStatus := status
+		if relockedSeatIDs[seatID] {
+			seatStatus.StatusCode = domain.SeatStatusLocked
+		}
+		if err := createSeatLog(ctx, tx, seatLogParams, seatStatusChanges(lockedSeat, seatStatus)); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return &domain.SeatsStatusChange{SeatIDs: cSeatIDs, PreviousStatuses: previousStatuses}, nil
 }
 
 // DeleteSeats deletes seats
@@ -184,6 +368,13 @@ func (s *Storage) DeleteSeats(ctx context.Context, ids *domain.IDs, seatIDs []st
 	defer span.End()
 
 	err := s.execTx(ctx, func(tx *Queries) error {
+		lockedSeats, err := tx.LockSeatsForUpdate(ctx, LockSeatsForUpdateParams{OrgID: ids.OrgID, SeatIds: seatIDs})
+		if err != nil {
+			return err
+		}
+		if err := checkLockedSeats(seatIDs, lockedSeats); err != nil {
+			return err
+		}
 		deleteSeatParams := DeleteSeatsParams{
 			Ids:         seatIDs,
 			OrgID:       ids.OrgID,
@@ -193,16 +384,25 @@ func (s *Storage) DeleteSeats(ctx context.Context, ids *domain.IDs, seatIDs []st
 		if err := tx.DeleteSeats(ctx, deleteSeatParams); err != nil {
 			return err
 		}
+		// holds of deleted seats would keep their tokens from being released or converted
+		if err := tx.DeleteSeatHoldsBySeatIDs(ctx, DeleteSeatHoldsBySeatIDsParams{OrgID: ids.OrgID, SeatIds: seatIDs}); err != nil {
+			return err
+		}
 
-		for _, seatID := range seatIDs {
-			seatLogParams := CreateSeatLogParams{
-				ID:        idgen.New("sl"),
-				SeatID:    seatID,
-				UserID:    nullString(ids.UserID),
-				Action:    nullString("deleted"),
-				CreatedAt: t,
+		for _, ls := range lockedSeats {
+			seatLogParams := InsertSeatLogParams{
+				ID:            idgen.New("sl"),
+				OrgID:         ids.OrgID,
+				SeatID:        ls.ID,
+				UserID:        nullString(ids.UserID),
+				SeatingPlanID: nullString(ls.SeatingPlanID),
+				OrderID:       ls.OrderID,
+				Action:        nullString("deleted"),
+				CreatedAt:     t,
 			}
-			if _, err := tx.CreateSeatLog(ctx, seatLogParams); err != nil {
+			changes := domain.SeatChanges{}
+			changes.Add(domain.SeatFieldDeletedAt, nullTimeValue(ls.DeletedAt), t.UTC())
+			if err := createSeatLog(ctx, tx, seatLogParams, changes); err != nil {
 				return err
 			}
 		}
@@ -218,64 +418,358 @@ func (s *Storage) DeleteSeats(ctx context.Context, ids *domain.IDs, seatIDs []st
 	return err
 }
 
-// GetSeats returns seats
-func (s *Storage) GetSeats(ctx context.Context, filter *domain.SeatsFilter, limit, offset int64) ([]domain.Seat, error) {
-	getSeatsParams := GetSeatsParams{}
-	if filter.Query != nil {
-		getSeatsParams.IsQuery = true
-		getSeatsParams.Query = *filter.Query
-	}
-	if filter.OrgID != nil {
-		getSeatsParams.IsOrgID = true
-		getSeatsParams.OrgID = *filter.OrgID
+// RestoreSeats restores soft deleted seats, it returns ids of restored seats. Not deleted seats are left as they are,
+// unknown seats fail the restore with ErrSeatNotFound.
+func (s *Storage) RestoreSeats(ctx context.Context, ids *domain.IDs, seatIDs []string, t time.Time) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.RestoreSeats")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("seatIDs").String(spew.Sdump(seatIDs)),
+	)
+	defer span.End()
+
+	var restoredSeatIDs []string
+	err := s.execTx(ctx, func(tx *Queries) error {
+		lockedSeats, err := tx.LockSeatsForUpdate(ctx, LockSeatsForUpdateParams{OrgID: ids.OrgID, SeatIds: seatIDs})
+		if err != nil {
+			return err
+		}
+		if missingSeatIDs := missingLockedSeats(seatIDs, lockedSeats); len(missingSeatIDs) > 0 {
+			return &domain.SeatError{OrigError: domain.ErrSeatNotFound, SeatIDs: missingSeatIDs}
+		}
+		rows, err := tx.RestoreSeats(ctx, RestoreSeatsParams{
+			OrgID:       ids.OrgID,
+			SeatIds:     seatIDs,
+			UpdatedAt:   t,
+			UpdatedByID: nullString(ids.UserID),
+		})
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			seatLogParams := InsertSeatLogParams{
+				ID:            idgen.New("sl"),
+				OrgID:         ids.OrgID,
+				SeatID:        row.ID,
+				UserID:        nullString(ids.UserID),
+				SeatingPlanID: nullString(row.SeatingPlanID),
+				OrderID:       row.OrderID,
+				Action:        nullString("restored"),
+				CreatedAt:     t,
+			}
+			changes := domain.SeatChanges{}
+			changes.Add(domain.SeatFieldDeletedAt, nullTimeValue(row.DeletedAt), nil)
+			if err := createSeatLog(ctx, tx, seatLogParams, changes); err != nil {
+				return err
+			}
+			restoredSeatIDs = append(restoredSeatIDs, row.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("restore seats tx: %w", err)
 	}
-	if filter.SeatingPlanID != nil {
-		getSeatsParams.IsSeatingPlanID = true
-		getSeatsParams.SeatingPlanID = *filter.SeatingPlanID
-	}
-	if filter.IDs != nil {
-		getSeatsParams.IsIds = true
-		getSeatsParams.Ids = append(getSeatsParams.Ids, filter.IDs...)
-	}
-	if filter.OrderBy != nil {
-		if filter.Desc {
-			switch *filter.OrderBy {
-			case "name":
-				getSeatsParams.OrderByNameDesc = true
-			case "num":
-				getSeatsParams.OrderByNumDesc = true
-			default:
-				getSeatsParams.OrderByNameDesc = true
+
+	return restoredSeatIDs, nil
+}
+
+// PurgeDeletedSeats hard deletes seats of the org soft deleted before deletedBefore together with their holds,
+// seat logs are deleted as well when withLogs is set. Seats which were ever sold to an order or are linked from
+// offered or sold seats are kept and reported as refused. Live seats linked to the purged ones are unlinked at t.
+func (s *Storage) PurgeDeletedSeats(ctx context.Context, ids *domain.IDs, deletedBefore time.Time, withLogs bool, t time.Time) (*domain.SeatPurgeResult, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.PurgeDeletedSeats")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("deletedBefore").String(deletedBefore.String()),
+	)
+	defer span.End()
+
+	res := &domain.SeatPurgeResult{}
+	err := s.execTx(ctx, func(tx *Queries) error {
+		rows, err := tx.LockDeletedSeatsBefore(ctx, LockDeletedSeatsBeforeParams{OrgID: ids.OrgID, DeletedBefore: deletedBefore})
+		if err != nil {
+			return err
+		}
+		refused := make(map[string]bool)
+		var seatIDs []string
+		for _, row := range rows {
+			seatIDs = append(seatIDs, row.ID)
+			if row.OrderID.Valid || row.OrderItemID.Valid || row.SplitOrderID.Valid || row.HasOrderLogs {
+				refused[row.ID] = true
 			}
-		} else {
-			switch *filter.OrderBy {
-			case "name":
-				getSeatsParams.OrderByNameAsc = true
-			case "num":
-				getSeatsParams.OrderByNumAsc = true
-			default:
-				getSeatsParams.OrderByNameAsc = true
+		}
+		if len(seatIDs) == 0 {
+			return nil
+		}
+		linkedSeats, err := tx.LockLiveSeatsLinkedTo(ctx, LockLiveSeatsLinkedToParams{OrgID: ids.OrgID, SeatIds: seatIDs})
+		if err != nil {
+			return err
+		}
+		// seats linked from offered or sold seats are still part of a hold or an order
+		for _, ls := range linkedSeats {
+			if status := domain.SeatStatus(ls.StatusCode.Int32); status == domain.SeatStatusOffered || status == domain.SeatStatusSold {
+				refused[ls.LinkedSeatID] = true
 			}
 		}
+		for _, seatID := range seatIDs {
+			if refused[seatID] {
+				res.RefusedSeatIDs = append(res.RefusedSeatIDs, seatID)
+				continue
+			}
+			res.PurgedSeatIDs = append(res.PurgedSeatIDs, seatID)
+		}
+		if len(res.PurgedSeatIDs) == 0 {
+			return nil
+		}
+		// live seats are unlinked like by UpdateSeats, so they are logged and freed
+		var unlinkSeats []*domain.UpdateSeat
+		for _, ls := range linkedSeats {
+			if !refused[ls.LinkedSeatID] {
+				unlinkedSeatID := ""
+				unlinkSeats = append(unlinkSeats, &domain.UpdateSeat{ID: ls.ID, LinkedSeatID: &unlinkedSeatID})
+			}
+		}
+		if len(unlinkSeats) > 0 {
+			if err := updateSeats(ctx, tx, unlinkSeats, ids, t); err != nil {
+				return err
+			}
+		}
+		err = tx.DeleteSeatHoldsBySeatIDs(ctx, DeleteSeatHoldsBySeatIDsParams{OrgID: ids.OrgID, SeatIds: res.PurgedSeatIDs})
+		if err != nil {
+			return err
+		}
+		err = tx.UnlinkDeletedSeatsFrom(ctx, UnlinkDeletedSeatsFromParams{OrgID: ids.OrgID, SeatIds: res.PurgedSeatIDs})
+		if err != nil {
+			return err
+		}
+		err = tx.DeleteSeatWheelchairReleasesBySeatIDs(ctx, DeleteSeatWheelchairReleasesBySeatIDsParams{OrgID: ids.OrgID, SeatIds: res.PurgedSeatIDs})
+		if err != nil {
+			return err
+		}
+		if withLogs {
+			if err := tx.DeleteSeatLogsBySeatIDs(ctx, res.PurgedSeatIDs); err != nil {
+				return err
+			}
+		}
+		return tx.PurgeSeats(ctx, PurgeSeatsParams{OrgID: ids.OrgID, SeatIds: res.PurgedSeatIDs})
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("purge deleted seats tx: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetSeats returns seats, the attributes filter is optional and narrows the seats further or includes deleted seats
+func (s *Storage) GetSeats(ctx context.Context, filter *domain.SeatsFilter, limit, offset int64, attributes *domain.SeatAttributesFilter) ([]domain.Seat, error) {
+	query := &domain.SeatsQuery{SeatsFilter: *filter}
+	if attributes != nil {
+		query.SeatAttributesFilter = *attributes
 	}
-	getSeatsParams.Limit = limit
-	getSeatsParams.Offset = offset
+	return s.ListSeats(ctx, query, limit, offset)
+}
 
-	rows, err := s.queries.GetSeats(ctx, getSeatsParams)
+// ListSeats returns seats matching the query
+func (s *Storage) ListSeats(ctx context.Context, query *domain.SeatsQuery, limit, offset int64) ([]domain.Seat, error) {
+	listSeatsParams, err := seatsQueryToListParams(query)
+	if err != nil {
+		return nil, err
+	}
+	listSeatsParams.Limit = limit
+	listSeatsParams.Offset = offset
+
+	rows, err := s.queries.ListSeats(ctx, listSeatsParams)
 	if err != nil {
 		return nil, fmt.Errorf("query seats: %w", err)
 	}
 	pns := make([]domain.Seat, 0, len(rows))
 	for _, pn := range rows {
-		pns = append(pns, convertToDomainSeats(pn))
+		pns = append(pns, convertToDomainSeats(pn.Seat))
 	}
 
 	return pns, nil
 }
 
-// ClearOfferedExpiredSeats clears offered seats that are expired
+// GetSeatsPage returns a page of seats matching the query, pages are stable when seats are inserted concurrently
+func (s *Storage) GetSeatsPage(ctx context.Context, query *domain.SeatsQuery, page domain.SeatsPageRequest) (*domain.SeatsPage, error) {
+	if page.Limit < 1 {
+		return nil, fmt.Errorf("invalid seats page limit %d", page.Limit)
+	}
+	listSeatsParams, err := seatsQueryToListParams(query)
+	if err != nil {
+		return nil, err
+	}
+	signature := seatOrderSignature(listSeatsParams.OrderBy)
+	if page.Cursor != nil {
+		keys, err := decodeCursor(*page.Cursor, len(listSeatsParams.OrderBy)+1)
+		if err != nil {
+			return nil, err
+		}
+		// cursors of a differently ordered query point to a wrong position
+		if keys[0] != signature {
+			return nil, domain.ErrInvalidCursor
+		}
+		listSeatsParams.IsAfter = true
+		listSeatsParams.After = keys[1:]
+	}
+	// one more row tells whether there is a next page
+	listSeatsParams.Limit = page.Limit + 1
+
+	rows, err := s.queries.ListSeats(ctx, listSeatsParams)
+	if err != nil {
+		return nil, fmt.Errorf("query seats: %w", err)
+	}
+	res := &domain.SeatsPage{}
+	if int64(len(rows)) > page.Limit {
+		rows = rows[:page.Limit]
+		keys := []string{signature}
+		for _, term := range listSeatsParams.OrderBy {
+			keys = append(keys, seatSortColumns[term.Column].value(rows[len(rows)-1]))
+		}
+		nextCursor := encodeCursor(keys...)
+		res.NextCursor = &nextCursor
+	}
+	res.Seats = make([]domain.Seat, 0, len(rows))
+	for _, row := range rows {
+		res.Seats = append(res.Seats, convertToDomainSeats(row.Seat))
+	}
+	if page.WithTotal {
+		total, err := s.queries.CountSeats(ctx, listSeatsParams)
+		if err != nil {
+			return nil, fmt.Errorf("count seats: %w", err)
+		}
+		res.Total = &total
+	}
+
+	return res, nil
+}
+
+func seatsQueryToListParams(query *domain.SeatsQuery) (ListSeatsParams, error) {
+	listSeatsParams := ListSeatsParams{Deleted: query.Deleted}
+	if query.Query != nil {
+		listSeatsParams.IsQuery = true
+		listSeatsParams.Query = *query.Query
+	}
+	if query.OrgID != nil {
+		listSeatsParams.IsOrgID = true
+		listSeatsParams.OrgID = *query.OrgID
+	}
+	if query.SeatingPlanID != nil {
+		listSeatsParams.IsSeatingPlanID = true
+		listSeatsParams.SeatingPlanID = *query.SeatingPlanID
+	}
+	if query.IDs != nil {
+		listSeatsParams.IsIds = true
+		listSeatsParams.Ids = append(listSeatsParams.Ids, query.IDs...)
+	}
+	if query.StatusCodes != nil {
+		listSeatsParams.IsStatusCodes = true
+		for _, statusCode := range query.StatusCodes {
+			listSeatsParams.StatusCodes = append(listSeatsParams.StatusCodes, int32(statusCode))
+		}
+	}
+	if query.PriceCategoryID != nil {
+		if *query.PriceCategoryID == "" {
+			listSeatsParams.IsPriceCategoryIDNull = true
+		} else {
+			listSeatsParams.IsPriceCategoryID = true
+			listSeatsParams.PriceCategoryID = *query.PriceCategoryID
+		}
+	}
+	if query.SeatRowID != nil {
+		listSeatsParams.IsSeatRowID = true
+		listSeatsParams.SeatRowID = *query.SeatRowID
+	}
+	if query.SeatBlockID != nil {
+		listSeatsParams.IsSeatBlockID = true
+		listSeatsParams.SeatBlockID = *query.SeatBlockID
+	}
+	if query.SeatGateID != nil {
+		listSeatsParams.IsSeatGateID = true
+		listSeatsParams.SeatGateID = *query.SeatGateID
+	}
+	if query.Wheelchair != nil {
+		listSeatsParams.IsWheelchair = true
+		listSeatsParams.Wheelchair = *query.Wheelchair
+	}
+	if query.OrderID != nil {
+		listSeatsParams.IsOrderID = true
+		listSeatsParams.OrderID = *query.OrderID
+	}
+	if query.HasLinkedSeat != nil {
+		listSeatsParams.IsLinkedSeat = true
+		listSeatsParams.HasLinkedSeat = *query.HasLinkedSeat
+	}
+	orderBy, err := seatOrderTerms(query)
+	if err != nil {
+		return ListSeatsParams{}, err
+	}
+	listSeatsParams.OrderBy = orderBy
+	return listSeatsParams, nil
+}
+
+// seatOrderTerms returns the sort terms of the query, seats are ordered by name by default. Without Sort the keys
+// are read from OrderBy, which lists them separated by commas, a key prefixed by - is ordered opposite to Desc.
+func seatOrderTerms(query *domain.SeatsQuery) ([]SeatOrderTerm, error) {
+	sort := query.Sort
+	if len(sort) == 0 {
+		orderBy := string(domain.SeatSortName)
+		if query.OrderBy != nil {
+			orderBy = *query.OrderBy
+		}
+		for _, key := range strings.Split(orderBy, ",") {
+			key = strings.TrimSpace(key)
+			desc := query.Desc
+			if strings.HasPrefix(key, "-") {
+				key, desc = key[1:], !desc
+			}
+			sort = append(sort, domain.SeatSort{Key: domain.SeatSortKey(key), Desc: desc})
+		}
+	}
+	terms := make([]SeatOrderTerm, 0, len(sort)+1)
+	for _, seatSort := range sort {
+		columns, ok := seatSortKeyColumns[seatSort.Key]
+		if !ok {
+			return nil, &domain.SeatSortKeyError{Key: seatSort.Key}
+		}
+		for _, column := range columns {
+			terms = append(terms, SeatOrderTerm{Column: column, Desc: seatSort.Desc})
+		}
+	}
+	// id makes the order total so that pages never skip or repeat seats
+	terms = append(terms, SeatOrderTerm{Column: "id", Desc: sort[len(sort)-1].Desc})
+	return terms, nil
+}
+
+func seatOrderSignature(terms []SeatOrderTerm) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term.Column
+		if term.Desc {
+			parts[i] += " desc"
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ClearOfferedExpiredSeats releases seats of all orgs offered longer than domain.DefaultSeatOfferExpiry like
+// ReleaseExpiredOfferedSeats does, so that the released seats are validated and logged.
+//
+// Deprecated: use ReleaseExpiredOfferedSeats, which takes the expiry of the org.
 func (s *Storage) ClearOfferedExpiredSeats(ctx context.Context) error {
-	return s.queries.ClearOfferedExpiredSeats(ctx)
+	orgIDs, err := s.GetOrgIDsWithOfferedSeats(ctx)
+	if err != nil {
+		return err
+	}
+	t := time.Now()
+	for _, orgID := range orgIDs {
+		if _, err := s.ReleaseExpiredOfferedSeats(ctx, &domain.IDs{OrgID: orgID}, t.Add(-domain.DefaultSeatOfferExpiry), t); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetSeatsBySeatingPlanID returns seats by seating plan id
@@ -482,7 +976,7 @@ func convertToDomainSeats(seatRow Seat) domain.Seat {
 func (s *Storage) CreateSeatLog(ctx context.Context, ids *domain.IDs, sg *domain.SeatLog, t time.Time) error {
 	insertSeatLogParams := seatLogToCreateParams(ids, sg, t)
 
-	_, err := s.queries.CreateSeatLog(ctx, insertSeatLogParams)
+	err := s.queries.InsertSeatLog(ctx, insertSeatLogParams)
 	if err != nil {
 		return err
 	}
@@ -490,29 +984,115 @@ func (s *Storage) CreateSeatLog(ctx context.Context, ids *domain.IDs, sg *domain
 	return err
 }
 
-// GetSeatLogs returns a seat log
-func (s *Storage) GetSeatLogs(ctx context.Context, limit, offset int64) ([]*domain.SeatLog, error) {
-	getSeatLogsParams := GetSeatLogsParams{}
-	getSeatLogsParams.Limit = limit
-	getSeatLogsParams.Offset = offset
-
-	rows, err := s.queries.GetSeatLogs(ctx, getSeatLogsParams)
+// GetSeatLogs returns seat logs of the org ordered by creation time
+//
+// Deprecated: use ListSeatLogs, offsets get slow on large seat logs.
+func (s *Storage) GetSeatLogs(ctx context.Context, orgID string, limit, offset int64) ([]*domain.SeatLog, error) {
+	if orgID == "" {
+		return nil, domain.ErrSeatLogsOrgRequired
+	}
+	rows, err := s.queries.GetOrgSeatLogs(ctx, GetOrgSeatLogsParams{OrgID: orgID, Limit: limit, Offset: offset})
 	if err != nil {
 		return nil, fmt.Errorf("query seat logs: %w", err)
 	}
 	pns := make([]*domain.SeatLog, 0, len(rows))
 	for _, pn := range rows {
-		pns = append(pns, convertSeatLogsRowToDomainSeatLogs(pn))
+		pns = append(pns, &domain.SeatLog{
+			ID:            pn.ID,
+			SeatID:        pn.SeatID,
+			Action:        pn.Action.String,
+			OrderID:       pn.OrderID.String,
+			SeatingPlanID: pn.SeatingPlanID.String,
+		})
 	}
 
 	return pns, nil
 }
 
+// ListSeatLogs returns a page of seat logs of the org matching the filter ordered by creation time,
+// logs created at the same time are returned in the order they were written
+func (s *Storage) ListSeatLogs(ctx context.Context, filter *domain.SeatLogsFilter, limit int64) (*domain.SeatLogsPage, error) {
+	if filter.OrgID == "" {
+		return nil, domain.ErrSeatLogsOrgRequired
+	}
+	if limit < 1 {
+		return nil, fmt.Errorf("invalid seat logs limit %d", limit)
+	}
+	getSeatLogsParams := GetSeatLogsPageParams{OrgID: filter.OrgID}
+	if filter.SeatID != nil {
+		getSeatLogsParams.IsSeatID = true
+		getSeatLogsParams.SeatID = *filter.SeatID
+	}
+	if filter.SeatingPlanID != nil {
+		getSeatLogsParams.IsSeatingPlanID = true
+		getSeatLogsParams.SeatingPlanID = *filter.SeatingPlanID
+	}
+	if filter.OrderID != nil {
+		getSeatLogsParams.IsOrderID = true
+		getSeatLogsParams.OrderID = *filter.OrderID
+	}
+	if filter.UserID != nil {
+		getSeatLogsParams.IsUserID = true
+		getSeatLogsParams.UserID = *filter.UserID
+	}
+	if filter.CreatedFrom != nil {
+		getSeatLogsParams.IsCreatedFrom = true
+		getSeatLogsParams.CreatedFrom = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		getSeatLogsParams.IsCreatedTo = true
+		getSeatLogsParams.CreatedTo = *filter.CreatedTo
+	}
+	if filter.Cursor != nil {
+		keys, err := decodeCursor(*filter.Cursor, 2)
+		if err != nil {
+			return nil, err
+		}
+		afterCreatedAt, err := decodeCursorTime(keys[0])
+		if err != nil {
+			return nil, err
+		}
+		afterSeq, err := strconv.ParseInt(keys[1], 10, 64)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		getSeatLogsParams.IsAfter = true
+		getSeatLogsParams.AfterCreatedAt = afterCreatedAt
+		getSeatLogsParams.AfterSeq = afterSeq
+	}
+	// one more row tells whether there is a next page
+	getSeatLogsParams.Limit = limit + 1
+
+	rows, err := s.queries.GetSeatLogsPage(ctx, getSeatLogsParams)
+	if err != nil {
+		return nil, fmt.Errorf("query seat logs: %w", err)
+	}
+	page := &domain.SeatLogsPage{}
+	if int64(len(rows)) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		nextCursor := encodeCursor(encodeCursorTime(last.CreatedAt), strconv.FormatInt(last.Seq, 10))
+		page.NextCursor = &nextCursor
+	}
+	page.Logs = make([]*domain.SeatLogEntry, 0, len(rows))
+	for _, row := range rows {
+		entry, err := convertSeatLogsPageRowToDomainSeatLogEntry(row)
+		if err != nil {
+			return nil, fmt.Errorf("decode seat log %v: %w", row.ID, err)
+		}
+		page.Logs = append(page.Logs, entry)
+	}
+
+	return page, nil
+}
+
 // GetBestSeatsByPosition returns the best seats by position
+//
+// Deprecated: use GetBestSeatsByReferencePoints, which takes the reference points from the seat scoring config
+// of the seating plan.
 func (s *Storage) GetBestSeatsByPosition(ctx context.Context, splID, priceCategoryID string, refPointX, refPointY int32) ([]domain.BestSeat, error) {
 	statusCode := int32(domain.SeatStatusAvailable)
-	var limit int32 = 1000
-	reqParams := getBestSeatsByPositionParams(splID, priceCategoryID, statusCode, refPointX, refPointY, limit)
+	reqParams := getBestSeatsByPositionParams(splID, priceCategoryID, statusCode, refPointX, refPointY, DefaultBestSeatsLimit)
 	rows, err := s.queries.GetBestSeatsByPosition(ctx, reqParams)
 	if err != nil {
 		return nil, fmt.Errorf("query qty of best seats per price: %w", err)
@@ -524,6 +1104,33 @@ func (s *Storage) GetBestSeatsByPosition(ctx context.Context, splID, priceCatego
 	return res, nil
 }
 
+// DefaultBestSeatsLimit is the number of best seats returned by GetBestSeatsByReferencePoints when no limit is given
+const DefaultBestSeatsLimit int32 = 1000
+
+// GetBestSeatsByReferencePoints returns up to limit best seats ordered by their distance from the reference points
+// of the seat scoring config of the seating plan ids.SplID, seating plans without reference points order seats by
+// their distance from the center of their row like the default config of best seats does. Limit below 1 returns
+// DefaultBestSeatsLimit seats, IterateBestSeatsByPosition returns all of them.
+func (s *Storage) GetBestSeatsByReferencePoints(ctx context.Context, ids *domain.IDs, priceCategoryID string, limit int32) ([]domain.BestSeat, error) {
+	if limit < 1 {
+		limit = DefaultBestSeatsLimit
+	}
+	config, err := s.GetSeatScoringConfig(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	it := s.IterateBestSeatsByPosition(ids, priceCategoryID, config.ReferencePoints, limit)
+	var res []domain.BestSeat
+	for int32(len(res)) < limit && it.Next(ctx) {
+		seat := it.Seat()
+		res = append(res, domain.BestSeat{SeatID: seat.ID, SeatNum: int(seat.Num), SeatRowID: *seat.SeatRowID})
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // GetRowSeatsBySeatingPlanID returns the seats for the rows in the given seatingPlanID
 func (s *Storage) GetRowSeatsBySeatingPlanID(ctx context.Context, splID, orgID, rowID string) ([]domain.Seat, error) {
 	statusCode := int32(domain.SeatStatusAvailable)
@@ -573,9 +1180,31 @@ func convertSeatLogsRowToDomainSeatLogs(s SeatLog) *domain.SeatLog {
 	return seatLog
 }
 
-func seatLogToCreateParams(ids *domain.IDs, log *domain.SeatLog, t time.Time) CreateSeatLogParams {
-	createSeatLogParams := CreateSeatLogParams{
+func convertSeatLogsPageRowToDomainSeatLogEntry(s GetSeatLogsPageRow) (*domain.SeatLogEntry, error) {
+	entry := &domain.SeatLogEntry{
+		SeatLog: domain.SeatLog{
+			ID:            s.ID,
+			SeatID:        s.SeatID,
+			Action:        s.Action.String,
+			OrderID:       s.OrderID.String,
+			SeatingPlanID: s.SeatingPlanID.String,
+		},
+		UserID:    s.UserID.String,
+		CreatedAt: s.CreatedAt,
+	}
+	if len(s.Changes) > 0 {
+		if err := json.Unmarshal(s.Changes, &entry.Changes); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+func seatLogToCreateParams(ids *domain.IDs, log *domain.SeatLog, t time.Time) InsertSeatLogParams {
+	createSeatLogParams := InsertSeatLogParams{
 		ID:            ids.ID,
+		OrgID:         ids.OrgID,
 		OrderID:       nullString(log.OrderID),
 		Action:        nullString(log.Action),
 		SeatID:        log.SeatID,
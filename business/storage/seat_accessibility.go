@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetSeatAccessibilityRules returns accessibility rules of the org, orgs without rules get rules which restrict nothing
+func (s *Storage) GetSeatAccessibilityRules(ctx context.Context, orgID string) (*domain.SeatAccessibilityRules, error) {
+	row, err := s.queries.GetSeatAccessibilityRules(ctx, orgID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &domain.SeatAccessibilityRules{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query seat accessibility rules: %w", err)
+	}
+	return &domain.SeatAccessibilityRules{
+		AccessibilityPriceNameIDs: row.AccessibilityPriceNameIds,
+		RequireCompanion:          row.RequireCompanion,
+		ReleaseBeforeEvent:        time.Duration(row.ReleaseBeforeEventMinutes) * time.Minute,
+	}, nil
+}
+
+// SetSeatAccessibilityRules sets accessibility rules of the org ids.OrgID
+func (s *Storage) SetSeatAccessibilityRules(ctx context.Context, ids *domain.IDs, rules *domain.SeatAccessibilityRules, t time.Time) error {
+	ctx, span := s.tracer.Start(ctx, "storage.SetSeatAccessibilityRules")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("rules").String(spew.Sdump(rules)),
+	)
+	defer span.End()
+
+	err := s.queries.UpsertSeatAccessibilityRules(ctx, UpsertSeatAccessibilityRulesParams{
+		OrgID:                     ids.OrgID,
+		AccessibilityPriceNameIds: rules.AccessibilityPriceNameIDs,
+		RequireCompanion:          rules.RequireCompanion,
+		ReleaseBeforeEventMinutes: int32(rules.ReleaseBeforeEvent / time.Minute),
+		CreatedAt:                 t,
+		CreatedByID:               ids.UserID,
+		UpdatedByID:               nullString(ids.UserID),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("upsert seat accessibility rules: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseWheelchairSeats releases wheelchair spaces of all orgs to general sale once an upcoming event of their
+// seating plan starts within the release time of the org. Only spaces available for order are released, the release
+// is stored for the event and the seats keep their wheelchair flag, so that they're wheelchair spaces again for
+// the later events of the seating plan. The release is logged for every released seat.
+func (s *Storage) ReleaseWheelchairSeats(ctx context.Context, t time.Time) ([]domain.ReleasedSeat, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.ReleaseWheelchairSeats")
+	defer span.End()
+
+	var released []domain.ReleasedSeat
+	err := s.execTx(ctx, func(tx *Queries) error {
+		rows, err := tx.LockWheelchairSeatsToRelease(ctx, LockWheelchairSeatsToReleaseParams{
+			Now:                 t,
+			AvailableStatusCode: int32(domain.SeatStatusAvailable),
+			LockedStatusCode:    int32(domain.SeatStatusLocked),
+		})
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			err := tx.InsertSeatWheelchairRelease(ctx, InsertSeatWheelchairReleaseParams{
+				SeatID:        row.ID,
+				EventID:       row.EventID,
+				OrgID:         row.OrgID,
+				SeatingPlanID: row.SeatingPlanID,
+				ReleasedAt:    t,
+			})
+			if err != nil {
+				return err
+			}
+			err = createSeatLog(ctx, tx, InsertSeatLogParams{
+				ID:            idgen.New("sl"),
+				OrgID:         row.OrgID,
+				SeatID:        row.ID,
+				SeatingPlanID: nullString(row.SeatingPlanID),
+				Action:        nullString("wheelchair space released"),
+				CreatedAt:     t,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			released = append(released, domain.ReleasedSeat{SeatID: row.ID, OrgID: row.OrgID, SeatingPlanID: row.SeatingPlanID})
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("release wheelchair seats tx: %w", err)
+	}
+
+	return released, nil
+}
+
+// GetReleasedWheelchairSeatIDs returns ids of the seats released to general sale by ReleaseWheelchairSeats for
+// the current event of their seating plan at t
+func (s *Storage) GetReleasedWheelchairSeatIDs(ctx context.Context, ids *domain.IDs, seatIDs []string, t time.Time) ([]string, error) {
+	releasedSeatIDs, err := s.queries.GetReleasedWheelchairSeatIDs(ctx, GetReleasedWheelchairSeatIDsParams{
+		OrgID:   ids.OrgID,
+		SeatIds: seatIDs,
+		Now:     t,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query released wheelchair seats: %w", err)
+	}
+	return releasedSeatIDs, nil
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type SeatAccessibilityRule struct {
+	OrgID                     string
+	AccessibilityPriceNameIds []string
+	RequireCompanion          bool
+	ReleaseBeforeEventMinutes int32
+}
+
+const getSeatAccessibilityRules = `
+SELECT org_id, accessibility_price_name_ids, require_companion, release_before_event_minutes
+FROM seat_accessibility_rules
+WHERE org_id = $1
+`
+
+func (q *Queries) GetSeatAccessibilityRules(ctx context.Context, orgID string) (SeatAccessibilityRule, error) {
+	row := q.db.QueryRowContext(ctx, getSeatAccessibilityRules, orgID)
+	var i SeatAccessibilityRule
+	err := row.Scan(
+		&i.OrgID,
+		pq.Array(&i.AccessibilityPriceNameIds),
+		&i.RequireCompanion,
+		&i.ReleaseBeforeEventMinutes,
+	)
+	return i, err
+}
+
+const upsertSeatAccessibilityRules = `
+INSERT INTO seat_accessibility_rules (org_id, accessibility_price_name_ids, require_companion, release_before_event_minutes,
+                                      created_at, created_by_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (org_id) DO UPDATE
+SET accessibility_price_name_ids = EXCLUDED.accessibility_price_name_ids,
+    require_companion = EXCLUDED.require_companion,
+    release_before_event_minutes = EXCLUDED.release_before_event_minutes,
+    updated_at = $5,
+    updated_by_id = $7
+`
+
+type UpsertSeatAccessibilityRulesParams struct {
+	OrgID                     string
+	AccessibilityPriceNameIds []string
+	RequireCompanion          bool
+	ReleaseBeforeEventMinutes int32
+	CreatedAt                 time.Time
+	CreatedByID               string
+	UpdatedByID               sql.NullString
+}
+
+func (q *Queries) UpsertSeatAccessibilityRules(ctx context.Context, arg UpsertSeatAccessibilityRulesParams) error {
+	_, err := q.db.ExecContext(ctx, upsertSeatAccessibilityRules,
+		arg.OrgID,
+		pq.Array(arg.AccessibilityPriceNameIds),
+		arg.RequireCompanion,
+		arg.ReleaseBeforeEventMinutes,
+		arg.CreatedAt,
+		arg.CreatedByID,
+		arg.UpdatedByID,
+	)
+	return err
+}
+
+// lockWheelchairSeatsToRelease returns wheelchair spaces available for order of orgs releasing them before the event
+// with the upcoming events of their seating plan which start within the release time, spaces already released
+// for the event are left out
+const lockWheelchairSeatsToRelease = `
+SELECT seats.id, seats.org_id, seats.seating_plan_id, events.id
+FROM seats
+JOIN seat_accessibility_rules rules ON rules.org_id = seats.org_id
+JOIN events ON events.org_id = seats.org_id AND events.seating_plan_id = seats.seating_plan_id
+WHERE rules.release_before_event_minutes > 0
+  AND COALESCE(seats.wheelchair, false)
+  AND seats.deleted_at IS NULL
+  AND (seats.status_code = $2 OR (seats.status_code = $3 AND COALESCE(seats.linked_seat_id, '') <> ''))
+  AND NOT EXISTS (SELECT 1 FROM seat_holds WHERE seat_holds.seat_id = seats.id)
+  AND events.starts_at > $1::timestamp
+  AND events.starts_at <= $1::timestamp + make_interval(mins => rules.release_before_event_minutes)
+  AND NOT EXISTS (SELECT 1
+                  FROM seat_wheelchair_releases releases
+                  WHERE releases.seat_id = seats.id
+                    AND releases.event_id = events.id)
+ORDER BY seats.id, events.id
+FOR UPDATE OF seats
+`
+
+type LockWheelchairSeatsToReleaseParams struct {
+	Now                 time.Time
+	AvailableStatusCode int32
+	LockedStatusCode    int32
+}
+
+type LockWheelchairSeatsToReleaseRow struct {
+	ID            string
+	OrgID         string
+	SeatingPlanID string
+	EventID       string
+}
+
+func (q *Queries) LockWheelchairSeatsToRelease(ctx context.Context, arg LockWheelchairSeatsToReleaseParams) ([]LockWheelchairSeatsToReleaseRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockWheelchairSeatsToRelease, arg.Now, arg.AvailableStatusCode, arg.LockedStatusCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockWheelchairSeatsToReleaseRow
+	for rows.Next() {
+		var i LockWheelchairSeatsToReleaseRow
+		if err := rows.Scan(&i.ID, &i.OrgID, &i.SeatingPlanID, &i.EventID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertSeatWheelchairRelease = `
+INSERT INTO seat_wheelchair_releases (seat_id, event_id, org_id, seating_plan_id, released_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (seat_id, event_id) DO NOTHING
+`
+
+type InsertSeatWheelchairReleaseParams struct {
+	SeatID        string
+	EventID       string
+	OrgID         string
+	SeatingPlanID string
+	ReleasedAt    time.Time
+}
+
+func (q *Queries) InsertSeatWheelchairRelease(ctx context.Context, arg InsertSeatWheelchairReleaseParams) error {
+	_, err := q.db.ExecContext(ctx, insertSeatWheelchairRelease,
+		arg.SeatID,
+		arg.EventID,
+		arg.OrgID,
+		arg.SeatingPlanID,
+		arg.ReleasedAt,
+	)
+	return err
+}
+
+// getReleasedWheelchairSeatIDs returns the seats released for an event of their seating plan, the release lasts
+// until a later event of the seating plan starts
+const getReleasedWheelchairSeatIDs = `
+SELECT DISTINCT releases.seat_id
+FROM seat_wheelchair_releases releases
+JOIN events ON events.id = releases.event_id
+WHERE releases.org_id = $1
+  AND releases.seat_id = ANY($2::varchar[])
+  AND NOT EXISTS (SELECT 1
+                  FROM events later
+                  WHERE later.org_id = events.org_id
+                    AND later.seating_plan_id = events.seating_plan_id
+                    AND later.starts_at > events.starts_at
+                    AND later.starts_at <= $3::timestamp)
+ORDER BY releases.seat_id
+`
+
+type GetReleasedWheelchairSeatIDsParams struct {
+	OrgID   string
+	SeatIds []string
+	Now     time.Time
+}
+
+func (q *Queries) GetReleasedWheelchairSeatIDs(ctx context.Context, arg GetReleasedWheelchairSeatIDsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getReleasedWheelchairSeatIDs, arg.OrgID, pq.Array(arg.SeatIds), arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var seatID string
+		if err := rows.Scan(&seatID); err != nil {
+			return nil, err
+		}
+		items = append(items, seatID)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSeatWheelchairReleasesBySeatIDs = `
+DELETE FROM seat_wheelchair_releases
+WHERE org_id = $1 AND seat_id = ANY($2::varchar[])
+`
+
+type DeleteSeatWheelchairReleasesBySeatIDsParams struct {
+	OrgID   string
+	SeatIds []string
+}
+
+func (q *Queries) DeleteSeatWheelchairReleasesBySeatIDs(ctx context.Context, arg DeleteSeatWheelchairReleasesBySeatIDsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSeatWheelchairReleasesBySeatIDs, arg.OrgID, pq.Array(arg.SeatIds))
+	return err
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/proj/business/domain"
+)
+
+// BestSeatsIterator iterates available seats of a price category ordered by their distance from reference points,
+// seats are queried in batches when they are needed
+type BestSeatsIterator struct {
+	queries   *Queries
+	params    ListSeatsByDistanceParams
+	batchSize int64
+	batch     []ListSeatsByDistanceRow
+	row       ListSeatsByDistanceRow
+	done      bool
+	err       error
+}
+
+// IterateBestSeatsByPosition returns an iterator of seats available for order (see domain.SeatAvailableForOrder)
+// with a row of the seating plan ids.SplID and the price category, empty priceCategoryID iterates seats of all
+// price categories. Seats are ordered by their distance from the closest of the points divided by its weight like
+// the distance scorer of best seats does, without points they're ordered by their distance from the center of their
+// row like the centrality scorer does. Seats are queried in batches of batchSize seats, batchSize below 1 uses
+// DefaultBestSeatsLimit.
+func (s *Storage) IterateBestSeatsByPosition(ids *domain.IDs, priceCategoryID string, points []domain.SeatReferencePoint, batchSize int32) *BestSeatsIterator {
+	if batchSize < 1 {
+		batchSize = DefaultBestSeatsLimit
+	}
+	params := ListSeatsByDistanceParams{
+		ListSeatsParams: ListSeatsParams{
+			IsOrgID:         true,
+			OrgID:           ids.OrgID,
+			IsSeatingPlanID: true,
+			SeatingPlanID:   ids.SplID,
+			IsStatusCodes:   true,
+			// locked seats are filtered out unless they're locked because they're linked
+			StatusCodes: []int32{int32(domain.SeatStatusAvailable), int32(domain.SeatStatusLocked)},
+		},
+	}
+	params.ByRowCentrality = len(points) == 0
+	for _, point := range points {
+		params.RefPointXs = append(params.RefPointXs, point.X)
+		params.RefPointYs = append(params.RefPointYs, point.Y)
+		params.RefPointWeights = append(params.RefPointWeights, point.Weight)
+	}
+	if priceCategoryID != "" {
+		params.IsPriceCategoryID = true
+		params.PriceCategoryID = priceCategoryID
+	}
+	return &BestSeatsIterator{queries: s.queries, params: params, batchSize: int64(batchSize)}
+}
+
+// Next moves to the next seat, it returns false when there are no more seats or on error
+func (it *BestSeatsIterator) Next(ctx context.Context) bool {
+	for len(it.batch) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.params.Limit = it.batchSize
+		rows, err := it.queries.ListSeatsByDistance(ctx, it.params)
+		if err != nil {
+			it.err = fmt.Errorf("query seats by distance: %w", err)
+			return false
+		}
+		it.done = int64(len(rows)) < it.batchSize
+		if len(rows) > 0 {
+			last := rows[len(rows)-1]
+			it.params.IsAfter = true
+			it.params.AfterDistance = last.Distance
+			it.params.AfterID = last.ID
+		}
+		for _, row := range rows {
+			if row.SeatRowID.Valid && row.SeatRowID.String != "" && domain.SeatAvailableForOrder(convertToDomainSeats(row.Seat)) {
+				it.batch = append(it.batch, row)
+			}
+		}
+	}
+	it.row, it.batch = it.batch[0], it.batch[1:]
+	return true
+}
+
+// Seat returns the current seat
+func (it *BestSeatsIterator) Seat() domain.Seat {
+	return convertToDomainSeats(it.row.Seat)
+}
+
+// Distance returns the distance of the current seat from the closest point divided by its weight,
+// without points it's the distance from the center of the row from 0 in the center to 1 at the ends
+func (it *BestSeatsIterator) Distance() float64 {
+	return it.row.Distance
+}
+
+// Err returns the error which stopped the iteration
+func (it *BestSeatsIterator) Err() error {
+	return it.err
+}
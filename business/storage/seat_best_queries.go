@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type ListSeatsByDistanceParams struct {
+	ListSeatsParams
+	// RefPointXs, RefPointYs and RefPointWeights are the reference points, the distance of a seat is its distance
+	// from the closest reference point divided by the weight of the point
+	RefPointXs      []int32
+	RefPointYs      []int32
+	RefPointWeights []float64
+	// ByRowCentrality orders seats by their distance from the center of their row instead,
+	// from 0 in the center to 1 at the ends
+	ByRowCentrality bool
+	// AfterDistance and AfterID are the distance and the id of the last seat of the previous batch
+	IsAfter       bool
+	AfterDistance float64
+	AfterID       string
+}
+
+type ListSeatsByDistanceRow struct {
+	Seat
+	Distance float64
+}
+
+// ListSeatsByDistance returns seats matching the filters of the params ordered by their distance from the reference
+// points or from the center of their row. Distances are returned with the seats, so that the next batch continues
+// after the exact distance.
+func (q *Queries) ListSeatsByDistance(ctx context.Context, arg ListSeatsByDistanceParams) ([]ListSeatsByDistanceRow, error) {
+	b := &seatsQueryBuilder{}
+	// the distance is double precision, so that the next batch continues after the exact distance
+	distance := `COALESCE((SELECT LEAST(abs(COALESCE(seats.x, 0) - (MIN(r.x) + MAX(r.x)) / 2.0) / NULLIF((MAX(r.x) - MIN(r.x)) / 2.0, 0), 1)
+                FROM seats r
+                WHERE r.seat_row_id = seats.seat_row_id
+                  AND r.deleted_at IS NULL), 0)::double precision`
+	if !arg.ByRowCentrality {
+		refPoints := fmt.Sprintf("unnest(%s::integer[], %s::integer[], %s::double precision[]) AS p(px, py, pw)",
+			b.arg(pq.Array(arg.RefPointXs)), b.arg(pq.Array(arg.RefPointYs)), b.arg(pq.Array(arg.RefPointWeights)))
+		distance = fmt.Sprintf("COALESCE((SELECT MIN(sqrt(power(COALESCE(x, 0) - px, 2) + power(COALESCE(y, 0) - py, 2)) / pw) FROM %s), 0)", refPoints)
+	}
+	b.filter(arg.ListSeatsParams)
+	var after string
+	if arg.IsAfter {
+		afterDistance := b.arg(arg.AfterDistance)
+		after = fmt.Sprintf("\nWHERE distance > %[1]s::double precision OR (distance = %[1]s::double precision AND id > %[2]s)", afterDistance, b.arg(arg.AfterID))
+	}
+	query := fmt.Sprintf("SELECT %[1]s, distance\nFROM (SELECT %[1]s, %[2]s AS distance\n      FROM seats\n      %[3]s) seats%[4]s\nORDER BY distance, id\nLIMIT %[5]s",
+		seatColumns, distance, b.whereClause(), after, b.arg(arg.Limit))
+	rows, err := q.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSeatsByDistanceRow
+	for rows.Next() {
+		var i ListSeatsByDistanceRow
+		if err := rows.Scan(append(seatScanDest(&i.Seat), &i.Distance)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// cloneSeatsBatchSize is the number of source seats read at once while cloning a seating plan
+const cloneSeatsBatchSize = 1000
+
+// CloneSeatingPlanSeats copies not deleted seats of the source seating plan to the destination one of the org,
+// the copies are available and have no order. Linked seats are linked to the copies of their linked seats,
+// seat groups and price categories are replaced by their counterparts in refs. The result maps source seat ids
+// to ids of their copies.
+func (s *Storage) CloneSeatingPlanSeats(ctx context.Context, ids *domain.IDs, srcSeatingPlanID, dstSeatingPlanID string, refs domain.SeatCloneRefs, t time.Time) (map[string]string, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.CloneSeatingPlanSeats")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("srcSeatingPlanID").String(srcSeatingPlanID),
+		attribute.Key("dstSeatingPlanID").String(dstSeatingPlanID),
+	)
+	defer span.End()
+
+	if srcSeatingPlanID == dstSeatingPlanID {
+		return nil, errors.New("can't clone seats of a seating plan to itself")
+	}
+
+	var clonedSeatIDs map[string]string
+	err := s.execTx(ctx, func(tx *Queries) error {
+		exists, err := tx.SeatingPlanExists(ctx, SeatingPlanExistsParams{ID: dstSeatingPlanID, OrgID: ids.OrgID})
+		if err != nil {
+			return fmt.Errorf("query seating plan: %w", err)
+		}
+		if !exists {
+			return domain.ErrSeatCloneDestinationNotFound
+		}
+		srcSeats, err := seatingPlanSeats(ctx, tx, ids.OrgID, srcSeatingPlanID)
+		if err != nil {
+			return err
+		}
+		clonedSeatIDs = make(map[string]string, len(srcSeats))
+		for _, seat := range srcSeats {
+			clonedSeatIDs[seat.ID] = idgen.New("st")
+		}
+		newSeats := make([]*domain.NewSeat, len(srcSeats))
+		for i, seat := range srcSeats {
+			newSeats[i], err = seatToClone(seat, clonedSeatIDs, refs, dstSeatingPlanID)
+			if err != nil {
+				return err
+			}
+		}
+		dstIDs := &domain.IDs{OrgID: ids.OrgID, UserID: ids.UserID, SplID: dstSeatingPlanID}
+		return createSeats(ctx, tx, dstIDs, newSeats, fmt.Sprintf("created (cloned from %s)", srcSeatingPlanID), t)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("clone seating plan seats tx: %w", err)
+	}
+
+	return clonedSeatIDs, nil
+}
+
+// seatingPlanSeats returns all not deleted seats of the seating plan
+func seatingPlanSeats(ctx context.Context, tx *Queries, orgID, seatingPlanID string) ([]Seat, error) {
+	params := ListSeatsParams{
+		IsOrgID:         true,
+		OrgID:           orgID,
+		IsSeatingPlanID: true,
+		SeatingPlanID:   seatingPlanID,
+		OrderBy:         []SeatOrderTerm{{Column: "id"}},
+		Limit:           cloneSeatsBatchSize,
+	}
+	var seats []Seat
+	for {
+		rows, err := tx.ListSeats(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			seats = append(seats, row.Seat)
+		}
+		if len(rows) < cloneSeatsBatchSize {
+			return seats, nil
+		}
+		params.IsAfter = true
+		params.After = []string{rows[len(rows)-1].ID}
+	}
+}
+
+// seatToClone returns an available copy of the seat in the seating plan without order, the linked seat id
+// is replaced by the id of the copy of the linked seat and seat groups and price categories by their counterparts
+func seatToClone(seat Seat, clonedSeatIDs map[string]string, refs domain.SeatCloneRefs, seatingPlanID string) (*domain.NewSeat, error) {
+	newSeat := &domain.NewSeat{
+		ID:            clonedSeatIDs[seat.ID],
+		SeatingPlanID: seatingPlanID,
+		Num:           seat.Num.Int32,
+		X:             seat.X.Int32,
+		Y:             seat.Y.Int32,
+		Name:          validStrP(seat.Name),
+		OverrideNum:   validStrP(seat.OverrideNum),
+		Description:   validStrP(seat.Description),
+		SvgPath:       validStrP(seat.SvgPath),
+		SvgTransform:  validStrP(seat.SvgTransform),
+	}
+	for _, ref := range []struct {
+		field string
+		srcID sql.NullString
+		dstID **string
+	}{
+		{domain.SeatFieldPriceCategoryID, seat.PriceCategoryID, &newSeat.PriceCategoryID},
+		{domain.SeatFieldSeatBlockID, seat.SeatBlockID, &newSeat.SeatBlockID},
+		{domain.SeatFieldSeatGateID, seat.SeatGateID, &newSeat.SeatGateID},
+		{domain.SeatFieldSeatRowID, seat.SeatRowID, &newSeat.SeatRowID},
+		{domain.SeatFieldBestSeatGroupID, seat.BestSeatGroupID, &newSeat.BestSeatGroupID},
+	} {
+		if ref.srcID.String == "" {
+			continue
+		}
+		dstID, ok := refs[ref.srcID.String]
+		if !ok {
+			return nil, &domain.SeatCloneRefError{SeatID: seat.ID, Field: ref.field, RefID: ref.srcID.String}
+		}
+		*ref.dstID = &dstID
+	}
+	if seat.Wheelchair.Valid {
+		wheelchair := seat.Wheelchair.Bool
+		newSeat.Wheelchair = &wheelchair
+	}
+	// links to seats outside of the source seating plan can't be remapped and are dropped,
+	// linked seats are locked the same way UpdateSeats locks them
+	if linkedSeatID, ok := clonedSeatIDs[seat.LinkedSeatID.String]; ok {
+		newSeat.LinkedSeatID = &linkedSeatID
+		newSeat.StatusCode = nullInt32P(int32(domain.SeatStatusLocked))
+	}
+	return newSeat, nil
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+)
+
+const seatingPlanExists = `
+SELECT EXISTS (SELECT 1 FROM seating_plans WHERE id = $1 AND org_id = $2)
+`
+
+type SeatingPlanExistsParams struct {
+	ID    string
+	OrgID string
+}
+
+// SeatingPlanExists tells whether the seating plan belongs to the org
+func (q *Queries) SeatingPlanExists(ctx context.Context, arg SeatingPlanExistsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, seatingPlanExists, arg.ID, arg.OrgID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
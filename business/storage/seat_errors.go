@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/proj/business/domain"
+)
+
+// pqUniqueViolation is the postgres error code of unique constraint violations
+const pqUniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
+
+// checkLockedSeats returns domain.ErrSeatNotFound when some of the seats weren't locked
+// and domain.ErrSeatDeleted when some of them are soft deleted
+func checkLockedSeats(seatIDs []string, lockedSeats []LockSeatsForUpdateRow) error {
+	if missingSeatIDs := missingLockedSeats(seatIDs, lockedSeats); len(missingSeatIDs) > 0 {
+		return &domain.SeatError{OrigError: domain.ErrSeatNotFound, SeatIDs: missingSeatIDs}
+	}
+	var deletedSeatIDs []string
+	for _, ls := range lockedSeats {
+		if ls.DeletedAt.Valid {
+			deletedSeatIDs = append(deletedSeatIDs, ls.ID)
+		}
+	}
+	if len(deletedSeatIDs) > 0 {
+		return &domain.SeatError{OrigError: domain.ErrSeatDeleted, SeatIDs: deletedSeatIDs}
+	}
+	return nil
+}
+
+// missingLockedSeats returns ids of the seats which weren't locked
+func missingLockedSeats(seatIDs []string, lockedSeats []LockSeatsForUpdateRow) []string {
+	lockedSeatIDs := make(map[string]bool, len(lockedSeats))
+	for _, ls := range lockedSeats {
+		lockedSeatIDs[ls.ID] = true
+	}
+	var missingSeatIDs []string
+	for _, seatID := range seatIDs {
+		if !lockedSeatIDs[seatID] {
+			missingSeatIDs = append(missingSeatIDs, seatID)
+		}
+	}
+	return missingSeatIDs
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetOrgIDsWithOfferedSeats returns ids of orgs which have offered seats
+func (s *Storage) GetOrgIDsWithOfferedSeats(ctx context.Context) ([]string, error) {
+	orgIDs, err := s.queries.GetOrgIDsWithOfferedSeats(ctx, int32(domain.SeatStatusOffered))
+	if err != nil {
+		return nil, fmt.Errorf("query orgs with offered seats: %w", err)
+	}
+	return orgIDs, nil
+}
+
+// ReleaseExpiredOfferedSeats makes seats of the org offered before offeredBefore available again
+func (s *Storage) ReleaseExpiredOfferedSeats(ctx context.Context, ids *domain.IDs, offeredBefore, t time.Time) ([]domain.ReleasedSeat, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.ReleaseExpiredOfferedSeats")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("offeredBefore").String(offeredBefore.String()),
+	)
+	defer span.End()
+
+	var released []domain.ReleasedSeat
+	err := s.execTx(ctx, func(tx *Queries) error {
+		var err error
+		released, err = releaseOfferedSeatsWithLogs(ctx, tx, ids, ReleaseOfferedSeatsParams{
+			IsOfferedBefore: true,
+			OfferedBefore:   offeredBefore,
+		}, "offer expired", t)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("release expired offered seats tx: %w", err)
+	}
+
+	return released, nil
+}
+
+// ReleaseExpiredSeatHolds removes expired seat holds of all orgs and makes the held seats available again
+func (s *Storage) ReleaseExpiredSeatHolds(ctx context.Context, t time.Time) ([]domain.ReleasedSeat, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.ReleaseExpiredSeatHolds")
+	defer span.End()
+
+	var released []domain.ReleasedSeat
+	err := s.execTx(ctx, func(tx *Queries) error {
+		expiredHolds, err := tx.DeleteExpiredSeatHolds(ctx, t)
+		if err != nil {
+			return err
+		}
+		seatIDsByOrg := make(map[string][]string)
+		for _, h := range expiredHolds {
+			seatIDsByOrg[h.OrgID] = append(seatIDsByOrg[h.OrgID], h.SeatID)
+		}
+		for orgID, seatIDs := range seatIDsByOrg {
+			orgReleased, err := releaseOfferedSeatsWithLogs(ctx, tx, &domain.IDs{OrgID: orgID}, ReleaseOfferedSeatsParams{
+				SeatIds: seatIDs,
+			}, "hold expired", t)
+			if err != nil {
+				return err
+			}
+			released = append(released, orgReleased...)
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("release expired seat holds tx: %w", err)
+	}
+
+	return released, nil
+}
+
+func releaseOfferedSeatsWithLogs(ctx context.Context, tx *Queries, ids *domain.IDs, params ReleaseOfferedSeatsParams, action string, t time.Time) ([]domain.ReleasedSeat, error) {
+	params.OrgID = ids.OrgID
+	params.OfferedStatusCode = int32(domain.SeatStatusOffered)
+	params.AvailableStatusCode = int32(domain.SeatStatusAvailable)
+	params.LockedStatusCode = int32(domain.SeatStatusLocked)
+	params.UpdatedAt = t
+	params.UpdatedByID = nullString(ids.UserID)
+	rows, err := tx.ReleaseOfferedSeats(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	released := make([]domain.ReleasedSeat, len(rows))
+	for i, row := range rows {
+		// the query writes the statuses directly, the transaction is rolled back unless they're declared
+		err := domain.ValidateSeatStatusTransition(row.ID, domain.SeatStatusOffered, domain.SeatStatus(row.StatusCode), false, row.LinkedSeatID.String != "")
+		if err != nil {
+			return nil, err
+		}
+		seatLogParams := InsertSeatLogParams{
+			ID:            idgen.New("sl"),
+			OrgID:         ids.OrgID,
+			SeatID:        row.ID,
+			UserID:        nullString(ids.UserID),
+			SeatingPlanID: nullString(row.SeatingPlanID),
+			Action:        nullString(action),
+			CreatedAt:     t,
+		}
+		changes := domain.SeatChanges{}
+		changes.Add(domain.SeatFieldStatusCode, params.OfferedStatusCode, row.StatusCode)
+		changes.Add(domain.SeatFieldOfferedAt, nullTimeValue(row.OfferedAt), nil)
+		if err := createSeatLog(ctx, tx, seatLogParams, changes); err != nil {
+			return nil, err
+		}
+		released[i] = domain.ReleasedSeat{SeatID: row.ID, OrgID: ids.OrgID, SeatingPlanID: row.SeatingPlanID}
+	}
+	return released, nil
+}
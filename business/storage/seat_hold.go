@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/proj/business/domain"
+	"github.com/proj/foundation/idgen"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HoldSeats holds available seats until t+ttl and returns the hold with a newly generated token.
+// Expired holds on the requested seats are released first, seats which are still held
+// or otherwise unavailable are reported with domain.SeatHoldConflictError. Seats linked to the requested ones
+// are held with them.
+func (s *Storage) HoldSeats(ctx context.Context, ids *domain.IDs, seatIDs []string, ttl time.Duration, t time.Time) (*domain.SeatHold, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.HoldSeats")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("seatIDs").String(spew.Sdump(seatIDs)),
+	)
+	defer span.End()
+
+	token, err := newSeatHoldToken()
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("generate seat hold token: %w", err)
+	}
+	hold := &domain.SeatHold{
+		Token:     token,
+		OrgID:     ids.OrgID,
+		ExpiresAt: t.Add(ttl),
+		CreatedAt: t,
+	}
+
+	err = s.execTx(ctx, func(tx *Queries) error {
+		// linked seats are held together with the requested ones
+		seatIDs, err := withLinkedSeats(ctx, tx, ids.OrgID, seatIDs)
+		if err != nil {
+			return err
+		}
+		hold.SeatIDs = seatIDs
+		lockedSeats, err := tx.LockSeatsForUpdate(ctx, LockSeatsForUpdateParams{OrgID: ids.OrgID, SeatIds: seatIDs})
+		if err != nil {
+			return err
+		}
+		if err := checkLockedSeats(seatIDs, lockedSeats); err != nil {
+			return err
+		}
+		releasedSeatIDs, err := releaseExpiredSeatHolds(ctx, tx, ids, seatIDs, t)
+		if err != nil {
+			return err
+		}
+		if unavailableSeatIDs := unavailableForHoldSeats(seatIDs, lockedSeats, releasedSeatIDs); len(unavailableSeatIDs) > 0 {
+			return &domain.SeatHoldConflictError{
+				OrigError: errors.New("seats are not available for hold"),
+				SeatIDs:   unavailableSeatIDs,
+			}
+		}
+		for _, ls := range lockedSeats {
+			insertSeatHoldParams := InsertSeatHoldParams{
+				ID:            idgen.New("sh"),
+				Token:         token,
+				OrgID:         ids.OrgID,
+				SeatingPlanID: ls.SeatingPlanID,
+				SeatID:        ls.ID,
+				ExpiresAt:     hold.ExpiresAt,
+				CreatedAt:     t,
+				CreatedByID:   ids.UserID,
+			}
+			if err := tx.InsertSeatHold(ctx, insertSeatHoldParams); err != nil {
+				return err
+			}
+		}
+		status := domain.UpdateSeatStatus{
+			SeatIDs:    seatIDs,
+			StatusCode: domain.SeatStatusOffered,
+			UpdatedAt:  t,
+		}
+		_, err = updateSeatsStatus(ctx, tx, *ids, status)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("hold seats tx: %w", err)
+	}
+
+	return hold, nil
+}
+
+// GetSeatHold returns the active hold by token
+func (s *Storage) GetSeatHold(ctx context.Context, ids *domain.IDs, token string, t time.Time) (*domain.SeatHold, error) {
+	rows, err := s.queries.GetSeatHoldsByToken(ctx, GetSeatHoldsByTokenParams{OrgID: ids.OrgID, Token: token, Now: t})
+	if err != nil {
+		return nil, fmt.Errorf("query seat hold: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrSeatHoldNotFound
+	}
+	return convertToDomainSeatHold(rows), nil
+}
+
+// ExtendSeatHold moves the expiry of an active hold to t+ttl
+func (s *Storage) ExtendSeatHold(ctx context.Context, ids *domain.IDs, token string, ttl time.Duration, t time.Time) (*domain.SeatHold, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.ExtendSeatHold")
+	span.SetAttributes(attribute.Key("ids").String(spew.Sdump(ids)))
+	defer span.End()
+
+	rows, err := s.queries.ExtendSeatHold(ctx, ExtendSeatHoldParams{
+		OrgID:     ids.OrgID,
+		Token:     token,
+		Now:       t,
+		ExpiresAt: t.Add(ttl),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("extend seat hold: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrSeatHoldNotFound
+	}
+	return convertToDomainSeatHold(rows), nil
+}
+
+// ReleaseSeatHold removes the hold and makes its seats available again
+func (s *Storage) ReleaseSeatHold(ctx context.Context, ids *domain.IDs, token string, t time.Time) error {
+	ctx, span := s.tracer.Start(ctx, "storage.ReleaseSeatHold")
+	span.SetAttributes(attribute.Key("ids").String(spew.Sdump(ids)))
+	defer span.End()
+
+	err := s.execTx(ctx, func(tx *Queries) error {
+		rows, err := tx.DeleteSeatHoldsByToken(ctx, DeleteSeatHoldsByTokenParams{OrgID: ids.OrgID, Token: token, Now: t})
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return domain.ErrSeatHoldNotFound
+		}
+		status := domain.UpdateSeatStatus{
+			SeatIDs:    seatHoldsSeatIDs(rows),
+			StatusCode: domain.SeatStatusAvailable,
+			UpdatedAt:  t,
+		}
+		_, err = updateSeatsStatus(ctx, tx, *ids, status)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("release seat hold tx: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertSeatHoldToOrder marks the seats of an active hold as sold to the order and removes the hold
+func (s *Storage) ConvertSeatHoldToOrder(ctx context.Context, ids *domain.IDs, token, orderID, orderItemID string, t time.Time) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "storage.ConvertSeatHoldToOrder")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("orderID").String(orderID),
+	)
+	defer span.End()
+
+	var seatIDs []string
+	err := s.execTx(ctx, func(tx *Queries) error {
+		rows, err := tx.DeleteSeatHoldsByToken(ctx, DeleteSeatHoldsByTokenParams{OrgID: ids.OrgID, Token: token, Now: t, OnlyActive: true})
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return domain.ErrSeatHoldNotFound
+		}
+		seatIDs = seatHoldsSeatIDs(rows)
+		status := domain.UpdateSeatStatus{
+			SeatIDs:        seatIDs,
+			StatusCode:     domain.SeatStatusSold,
+			AddOrderID:     &orderID,
+			AddOrderItemID: &orderItemID,
+			UpdatedAt:      t,
+		}
+		_, err = updateSeatsStatus(ctx, tx, *ids, status)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("convert seat hold to order tx: %w", err)
+	}
+
+	return seatIDs, nil
+}
+
+// releaseExpiredSeatHolds removes expired holds of the given seats and makes them available,
+// it returns ids of released seats
+func releaseExpiredSeatHolds(ctx context.Context, tx *Queries, ids *domain.IDs, seatIDs []string, t time.Time) ([]string, error) {
+	expiredHolds, err := tx.DeleteExpiredSeatHoldsBySeatIDs(ctx, DeleteExpiredSeatHoldsBySeatIDsParams{
+		OrgID:   ids.OrgID,
+		SeatIds: seatIDs,
+		Now:     t,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(expiredHolds) == 0 {
+		return nil, nil
+	}
+	releasedSeatIDs := seatHoldsSeatIDs(expiredHolds)
+	status := domain.UpdateSeatStatus{
+		SeatIDs:    releasedSeatIDs,
+		StatusCode: domain.SeatStatusAvailable,
+		UpdatedAt:  t,
+	}
+	if _, err := updateSeatsStatus(ctx, tx, *ids, status); err != nil {
+		return nil, err
+	}
+	return releasedSeatIDs, nil
+}
+
+func unavailableForHoldSeats(seatIDs []string, lockedSeats []LockSeatsForUpdateRow, releasedSeatIDs []string) []string {
+	available := make(map[string]bool, len(lockedSeats))
+	for _, ls := range lockedSeats {
+		available[ls.ID] = !ls.DeletedAt.Valid && (lockedSeatStatus(ls) == domain.SeatStatusAvailable || linkLockedSeat(ls))
+	}
+	for _, seatID := range releasedSeatIDs {
+		available[seatID] = true
+	}
+	var unavailableSeatIDs []string
+	for _, seatID := range seatIDs {
+		if !available[seatID] {
+			unavailableSeatIDs = append(unavailableSeatIDs, seatID)
+		}
+	}
+	return unavailableSeatIDs
+}
+
+func seatHoldsSeatIDs(holds []SeatHold) []string {
+	seatIDs := make([]string, len(holds))
+	for i, h := range holds {
+		seatIDs[i] = h.SeatID
+	}
+	return seatIDs
+}
+
+func convertToDomainSeatHold(holds []SeatHold) *domain.SeatHold {
+	return &domain.SeatHold{
+		Token:     holds[0].Token,
+		OrgID:     holds[0].OrgID,
+		SeatIDs:   seatHoldsSeatIDs(holds),
+		ExpiresAt: holds[0].ExpiresAt,
+		CreatedAt: holds[0].CreatedAt,
+	}
+}
+
+func newSeatHoldToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type SeatHold struct {
+	ID            string
+	Token         string
+	OrgID         string
+	SeatingPlanID string
+	SeatID        string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	CreatedByID   string
+}
+
+const seatHoldColumns = `id, token, org_id, seating_plan_id, seat_id, expires_at, created_at, created_by_id`
+
+const insertSeatHold = `
+INSERT INTO seat_holds (` + seatHoldColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type InsertSeatHoldParams struct {
+	ID            string
+	Token         string
+	OrgID         string
+	SeatingPlanID string
+	SeatID        string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+	CreatedByID   string
+}
+
+func (q *Queries) InsertSeatHold(ctx context.Context, arg InsertSeatHoldParams) error {
+	_, err := q.db.ExecContext(ctx, insertSeatHold,
+		arg.ID,
+		arg.Token,
+		arg.OrgID,
+		arg.SeatingPlanID,
+		arg.SeatID,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+		arg.CreatedByID,
+	)
+	return err
+}
+
+const getSeatHoldsByToken = `
+SELECT ` + seatHoldColumns + `
+FROM seat_holds
+WHERE org_id = $1 AND token = $2 AND expires_at > $3
+ORDER BY seat_id
+`
+
+type GetSeatHoldsByTokenParams struct {
+	OrgID string
+	Token string
+	Now   time.Time
+}
+
+func (q *Queries) GetSeatHoldsByToken(ctx context.Context, arg GetSeatHoldsByTokenParams) ([]SeatHold, error) {
+	return q.querySeatHolds(ctx, getSeatHoldsByToken, arg.OrgID, arg.Token, arg.Now)
+}
+
+const extendSeatHold = `
+UPDATE seat_holds
+SET expires_at = $4
+WHERE org_id = $1 AND token = $2 AND expires_at > $3
+RETURNING ` + seatHoldColumns
+
+type ExtendSeatHoldParams struct {
+	OrgID     string
+	Token     string
+	Now       time.Time
+	ExpiresAt time.Time
+}
+
+func (q *Queries) ExtendSeatHold(ctx context.Context, arg ExtendSeatHoldParams) ([]SeatHold, error) {
+	return q.querySeatHolds(ctx, extendSeatHold, arg.OrgID, arg.Token, arg.Now, arg.ExpiresAt)
+}
+
+const deleteSeatHoldsByToken = `
+DELETE FROM seat_holds
+WHERE org_id = $1 AND token = $2 AND (NOT $4::bool OR expires_at > $3)
+RETURNING ` + seatHoldColumns
+
+type DeleteSeatHoldsByTokenParams struct {
+	OrgID      string
+	Token      string
+	Now        time.Time
+	OnlyActive bool
+}
+
+func (q *Queries) DeleteSeatHoldsByToken(ctx context.Context, arg DeleteSeatHoldsByTokenParams) ([]SeatHold, error) {
+	return q.querySeatHolds(ctx, deleteSeatHoldsByToken, arg.OrgID, arg.Token, arg.Now, arg.OnlyActive)
+}
+
+const deleteExpiredSeatHoldsBySeatIDs = `
+DELETE FROM seat_holds
+WHERE org_id = $1 AND seat_id = ANY($2::varchar[]) AND expires_at <= $3
+RETURNING ` + seatHoldColumns
+
+type DeleteExpiredSeatHoldsBySeatIDsParams struct {
+	OrgID   string
+	SeatIds []string
+	Now     time.Time
+}
+
+func (q *Queries) DeleteExpiredSeatHoldsBySeatIDs(ctx context.Context, arg DeleteExpiredSeatHoldsBySeatIDsParams) ([]SeatHold, error) {
+	return q.querySeatHolds(ctx, deleteExpiredSeatHoldsBySeatIDs, arg.OrgID, pq.Array(arg.SeatIds), arg.Now)
+}
+
+func (q *Queries) querySeatHolds(ctx context.Context, query string, args ...interface{}) ([]SeatHold, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SeatHold
+	for rows.Next() {
+		var i SeatHold
+		if err := rows.Scan(
+			&i.ID,
+			&i.Token,
+			&i.OrgID,
+			&i.SeatingPlanID,
+			&i.SeatID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.CreatedByID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteExpiredSeatHolds = `
+DELETE FROM seat_holds
+WHERE expires_at <= $1
+RETURNING ` + seatHoldColumns
+
+func (q *Queries) DeleteExpiredSeatHolds(ctx context.Context, now time.Time) ([]SeatHold, error) {
+	return q.querySeatHolds(ctx, deleteExpiredSeatHolds, now)
+}
+
+const deleteSeatHoldsBySeatIDs = `
+DELETE FROM seat_holds
+WHERE org_id = $1 AND seat_id = ANY($2::varchar[])
+`
+
+type DeleteSeatHoldsBySeatIDsParams struct {
+	OrgID   string
+	SeatIds []string
+}
+
+func (q *Queries) DeleteSeatHoldsBySeatIDs(ctx context.Context, arg DeleteSeatHoldsBySeatIDsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSeatHoldsBySeatIDs, arg.OrgID, pq.Array(arg.SeatIds))
+	return err
+}
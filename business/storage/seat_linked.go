@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/proj/business/domain"
+)
+
+// GetLinkedSeatGroups returns groups of seats linked to the given seats, seats without links are left out
+func (s *Storage) GetLinkedSeatGroups(ctx context.Context, ids *domain.IDs, seatIDs []string) ([]domain.LinkedSeatGroup, error) {
+	rows, err := s.queries.ListLinkedSeats(ctx, ListLinkedSeatsParams{OrgID: ids.OrgID, SeatIds: seatIDs})
+	if err != nil {
+		return nil, fmt.Errorf("query linked seats: %w", err)
+	}
+	return linkedSeatGroups(rows), nil
+}
+
+// withLinkedSeats returns the seats followed by the seats linked to them, so that the whole groups are changed together
+func withLinkedSeats(ctx context.Context, tx *Queries, orgID string, seatIDs []string) ([]string, error) {
+	if len(seatIDs) == 0 {
+		return seatIDs, nil
+	}
+	rows, err := tx.ListLinkedSeats(ctx, ListLinkedSeatsParams{OrgID: orgID, SeatIds: seatIDs})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(rows))
+	res := make([]string, 0, len(rows))
+	for _, seatID := range seatIDs {
+		if !seen[seatID] {
+			seen[seatID] = true
+			res = append(res, seatID)
+		}
+	}
+	for _, row := range rows {
+		if !seen[row.ID] {
+			seen[row.ID] = true
+			res = append(res, row.ID)
+		}
+	}
+	return res, nil
+}
+
+// linkedSeatGroups splits the linked seats to groups of seats connected by links
+func linkedSeatGroups(rows []ListLinkedSeatsRow) []domain.LinkedSeatGroup {
+	parents := make(map[string]string, len(rows))
+	var find func(seatID string) string
+	find = func(seatID string) string {
+		parent, ok := parents[seatID]
+		if !ok || parent == seatID {
+			parents[seatID] = seatID
+			return seatID
+		}
+		root := find(parent)
+		parents[seatID] = root
+		return root
+	}
+	for _, row := range rows {
+		find(row.ID)
+		if row.LinkedSeatID != "" {
+			parents[find(row.ID)] = find(row.LinkedSeatID)
+		}
+	}
+	seatIDsByRoot := make(map[string][]string)
+	for _, row := range rows {
+		root := find(row.ID)
+		seatIDsByRoot[root] = append(seatIDsByRoot[root], row.ID)
+	}
+	var groups []domain.LinkedSeatGroup
+	for _, seatIDs := range seatIDsByRoot {
+		if len(seatIDs) < 2 {
+			continue
+		}
+		sort.Strings(seatIDs)
+		groups = append(groups, domain.LinkedSeatGroup{SeatIDs: seatIDs})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].SeatIDs[0] < groups[j].SeatIDs[0]
+	})
+	return groups
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+// listLinkedSeats follows links of the seats in both directions
+const listLinkedSeats = `
+WITH RECURSIVE linked AS (
+    SELECT id, linked_seat_id
+    FROM seats
+    WHERE org_id = $1 AND id = ANY($2::varchar[]) AND deleted_at IS NULL
+  UNION
+    SELECT s.id, s.linked_seat_id
+    FROM seats AS s
+    JOIN linked AS l ON s.id = l.linked_seat_id OR s.linked_seat_id = l.id
+    WHERE s.org_id = $1 AND s.deleted_at IS NULL
+)
+SELECT id, COALESCE(linked_seat_id, '')
+FROM linked
+ORDER BY id
+`
+
+type ListLinkedSeatsParams struct {
+	OrgID   string
+	SeatIds []string
+}
+
+type ListLinkedSeatsRow struct {
+	ID           string
+	LinkedSeatID string
+}
+
+// ListLinkedSeats returns the seats together with all seats linked to them directly or through other seats
+func (q *Queries) ListLinkedSeats(ctx context.Context, arg ListLinkedSeatsParams) ([]ListLinkedSeatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLinkedSeats, arg.OrgID, pq.Array(arg.SeatIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLinkedSeatsRow
+	for rows.Next() {
+		var i ListLinkedSeatsRow
+		if err := rows.Scan(&i.ID, &i.LinkedSeatID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
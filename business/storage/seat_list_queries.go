@@ -0,0 +1,400 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/proj/business/domain"
+)
+
+const seatColumns = `id, org_id, seating_plan_id, num, name, x, y, order_id, order_item_id, split_order_id,
+       price_category_id, status_code, offered_at, svg_transform, svg_path, wheelchair, override_num,
+       linked_seat_id, description, seat_row_id, seat_block_id, seat_gate_id, best_seat_group_id,
+       created_at, created_by_id, updated_at, updated_by_id, deleted_at, deleted_by_id`
+
+type ListSeatsParams struct {
+	IsQuery               bool
+	Query                 string
+	IsOrgID               bool
+	OrgID                 string
+	IsSeatingPlanID       bool
+	SeatingPlanID         string
+	IsIds                 bool
+	Ids                   []string
+	Deleted               domain.SeatDeletedFilter
+	IsStatusCodes         bool
+	StatusCodes           []int32
+	IsPriceCategoryID     bool
+	PriceCategoryID       string
+	IsPriceCategoryIDNull bool
+	IsSeatRowID           bool
+	SeatRowID             string
+	IsSeatBlockID         bool
+	SeatBlockID           string
+	IsSeatGateID          bool
+	SeatGateID            string
+	IsWheelchair          bool
+	Wheelchair            bool
+	IsOrderID             bool
+	OrderID               string
+	IsLinkedSeat          bool
+	HasLinkedSeat         bool
+	OrderBy               []SeatOrderTerm
+	// After holds the sort values of the last seat of the previous page, one per OrderBy term
+	IsAfter bool
+	After   []string
+	Limit   int64
+	Offset  int64
+}
+
+// ListSeatsRow is a seat with the names of its seat groups and price category, seats may be ordered by them
+type ListSeatsRow struct {
+	Seat
+	RowName           sql.NullString
+	BlockName         sql.NullString
+	PriceCategoryName sql.NullString
+}
+
+// SeatOrderTerm is a single sort key of a seats query
+type SeatOrderTerm struct {
+	Column string
+	Desc   bool
+}
+
+// seatSortColumn is a sortable seat column, expr never returns NULL so that keyset comparisons work
+type seatSortColumn struct {
+	expr string
+	cast string
+	// value returns the value of the column of the seat as stored in page cursors
+	value func(ListSeatsRow) string
+}
+
+var seatSortColumns = map[string]seatSortColumn{
+	"id": {
+		expr:  "id",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.ID },
+	},
+	"name": {
+		expr:  "COALESCE(name, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.Name.String },
+	},
+	"num": {
+		expr:  "COALESCE(num, 0)",
+		cast:  "integer",
+		value: func(s ListSeatsRow) string { return strconv.Itoa(int(s.Num.Int32)) },
+	},
+	"x": {
+		expr:  "COALESCE(x, 0)",
+		cast:  "integer",
+		value: func(s ListSeatsRow) string { return strconv.Itoa(int(s.X.Int32)) },
+	},
+	"y": {
+		expr:  "COALESCE(y, 0)",
+		cast:  "integer",
+		value: func(s ListSeatsRow) string { return strconv.Itoa(int(s.Y.Int32)) },
+	},
+	"status_code": {
+		expr:  "COALESCE(status_code, 0)",
+		cast:  "integer",
+		value: func(s ListSeatsRow) string { return strconv.Itoa(int(s.StatusCode.Int32)) },
+	},
+	"price_category_name": {
+		expr:  "COALESCE(price_category_name, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.PriceCategoryName.String },
+	},
+	"price_category_id": {
+		expr:  "COALESCE(price_category_id, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.PriceCategoryID.String },
+	},
+	"row_name": {
+		expr:  "COALESCE(row_name, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.RowName.String },
+	},
+	"seat_row_id": {
+		expr:  "COALESCE(seat_row_id, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.SeatRowID.String },
+	},
+	"block_name": {
+		expr:  "COALESCE(block_name, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.BlockName.String },
+	},
+	"seat_block_id": {
+		expr:  "COALESCE(seat_block_id, '')",
+		cast:  "varchar",
+		value: func(s ListSeatsRow) string { return s.SeatBlockID.String },
+	},
+	"created_at": {
+		expr:  "created_at",
+		cast:  "timestamp",
+		value: func(s ListSeatsRow) string { return s.CreatedAt.UTC().Format(time.RFC3339Nano) },
+	},
+	"updated_at": {
+		expr: "COALESCE(updated_at, created_at)",
+		cast: "timestamp",
+		value: func(s ListSeatsRow) string {
+			if s.UpdatedAt.Valid {
+				return s.UpdatedAt.Time.UTC().Format(time.RFC3339Nano)
+			}
+			return s.CreatedAt.UTC().Format(time.RFC3339Nano)
+		},
+	},
+}
+
+// seatSortKeyColumns maps sort keys of seats queries to sortable columns. Seat groups and price categories are
+// ordered by name, their id keeps seats of equally named ones together.
+var seatSortKeyColumns = map[domain.SeatSortKey][]string{
+	domain.SeatSortName:          {"name"},
+	domain.SeatSortNum:           {"num"},
+	domain.SeatSortX:             {"x"},
+	domain.SeatSortY:             {"y"},
+	domain.SeatSortStatus:        {"status_code"},
+	domain.SeatSortPriceCategory: {"price_category_name", "price_category_id"},
+	domain.SeatSortRow:           {"row_name", "seat_row_id"},
+	domain.SeatSortBlock:         {"block_name", "seat_block_id"},
+	domain.SeatSortCreatedAt:     {"created_at"},
+	domain.SeatSortUpdatedAt:     {"updated_at"},
+}
+
+// listSeatsFrom joins the names seats may be ordered by, the subquery keeps the seat columns unqualified
+const listSeatsFrom = `(SELECT seats.*, sr.name AS row_name, sb.name AS block_name, pc.name AS price_category_name
+      FROM seats
+      LEFT JOIN seat_groups sr ON sr.id = seats.seat_row_id
+      LEFT JOIN seat_groups sb ON sb.id = seats.seat_block_id
+      LEFT JOIN price_categories pc ON pc.id = seats.price_category_id) seats`
+
+// listSeatsNamesFrom returns the names columns and the FROM clause of the seats query. The names are only joined
+// when seats are ordered by them, other orders read the seats table alone so that its sort indexes are used.
+func listSeatsNamesFrom(terms []SeatOrderTerm) (string, string) {
+	for _, term := range terms {
+		switch term.Column {
+		case "row_name", "block_name", "price_category_name":
+			return "row_name, block_name, price_category_name", listSeatsFrom
+		}
+	}
+	return "NULL::varchar, NULL::varchar, NULL::varchar", "seats"
+}
+
+// seatsQueryBuilder collects conditions and positional arguments of a seats query
+type seatsQueryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg adds the argument and returns its placeholder
+func (b *seatsQueryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *seatsQueryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+func (b *seatsQueryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, "\n  AND ")
+}
+
+func (b *seatsQueryBuilder) filter(arg ListSeatsParams) {
+	if arg.IsQuery {
+		b.where("name ILIKE '%' || " + b.arg(arg.Query) + " || '%'")
+	}
+	if arg.IsOrgID {
+		b.where("org_id = " + b.arg(arg.OrgID))
+	}
+	if arg.IsSeatingPlanID {
+		b.where("seating_plan_id = " + b.arg(arg.SeatingPlanID))
+	}
+	if arg.IsIds {
+		b.where("id = ANY(" + b.arg(pq.Array(arg.Ids)) + "::varchar[])")
+	}
+	if arg.IsStatusCodes {
+		b.where("status_code = ANY(" + b.arg(pq.Array(arg.StatusCodes)) + "::integer[])")
+	}
+	if arg.IsPriceCategoryID {
+		b.where("price_category_id = " + b.arg(arg.PriceCategoryID))
+	}
+	if arg.IsPriceCategoryIDNull {
+		b.where("price_category_id IS NULL")
+	}
+	if arg.IsSeatRowID {
+		b.where("seat_row_id = " + b.arg(arg.SeatRowID))
+	}
+	if arg.IsSeatBlockID {
+		b.where("seat_block_id = " + b.arg(arg.SeatBlockID))
+	}
+	if arg.IsSeatGateID {
+		b.where("seat_gate_id = " + b.arg(arg.SeatGateID))
+	}
+	if arg.IsWheelchair {
+		b.where("COALESCE(wheelchair, false) = " + b.arg(arg.Wheelchair))
+	}
+	if arg.IsOrderID {
+		b.where("order_id = " + b.arg(arg.OrderID))
+	}
+	if arg.IsLinkedSeat {
+		if arg.HasLinkedSeat {
+			b.where("COALESCE(linked_seat_id, '') <> ''")
+		} else {
+			b.where("COALESCE(linked_seat_id, '') = ''")
+		}
+	}
+	switch arg.Deleted {
+	case domain.SeatDeletedExclude:
+		b.where("deleted_at IS NULL")
+	case domain.SeatDeletedOnly:
+		b.where("deleted_at IS NOT NULL")
+	}
+}
+
+// orderBy returns the ORDER BY clause for the terms, unknown columns are ignored
+func (b *seatsQueryBuilder) orderBy(terms []SeatOrderTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		column, ok := seatSortColumns[term.Column]
+		if !ok {
+			continue
+		}
+		direction := "ASC"
+		if term.Desc {
+			direction = "DESC"
+		}
+		parts = append(parts, column.expr+" "+direction)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// after adds the keyset condition selecting seats which follow the given sort values,
+// (a, b) > (x, y) is expanded to a > x OR (a = x AND b > y) as the terms may have different directions
+func (b *seatsQueryBuilder) after(terms []SeatOrderTerm, values []string) {
+	alternatives := make([]string, 0, len(terms))
+	for i, term := range terms {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			column := seatSortColumns[terms[j].Column]
+			conditions = append(conditions, fmt.Sprintf("%s = %s::%s", column.expr, b.arg(values[j]), column.cast))
+		}
+		column := seatSortColumns[term.Column]
+		operator := ">"
+		if term.Desc {
+			operator = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s %s::%s", column.expr, operator, b.arg(values[i]), column.cast))
+		alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+	}
+	b.where("(" + strings.Join(alternatives, " OR ") + ")")
+}
+
+// ListSeats returns seats matching the params, it builds the query from the set filters only
+func (q *Queries) ListSeats(ctx context.Context, arg ListSeatsParams) ([]ListSeatsRow, error) {
+	b := &seatsQueryBuilder{}
+	b.filter(arg)
+	if arg.IsAfter {
+		b.after(arg.OrderBy, arg.After)
+	}
+	names, from := listSeatsNamesFrom(arg.OrderBy)
+	query := fmt.Sprintf("SELECT %s, %s\nFROM %s\n%s\nORDER BY %s\nLIMIT %s OFFSET %s",
+		seatColumns, names, from, b.whereClause(), b.orderBy(arg.OrderBy), b.arg(arg.Limit), b.arg(arg.Offset))
+	rows, err := q.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSeatsRow
+	for rows.Next() {
+		var i ListSeatsRow
+		if err := rows.Scan(append(seatScanDest(&i.Seat), &i.RowName, &i.BlockName, &i.PriceCategoryName)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountSeats returns the number of seats matching the filters of the params
+func (q *Queries) CountSeats(ctx context.Context, arg ListSeatsParams) (int64, error) {
+	b := &seatsQueryBuilder{}
+	b.filter(arg)
+	query := fmt.Sprintf("SELECT count(*)\nFROM seats\n%s", b.whereClause())
+	var count int64
+	err := q.db.QueryRowContext(ctx, query, b.args...).Scan(&count)
+	return count, err
+}
+
+func (q *Queries) querySeats(ctx context.Context, query string, args ...interface{}) ([]Seat, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Seat
+	for rows.Next() {
+		var i Seat
+		if err := rows.Scan(seatScanDest(&i)...); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// seatScanDest returns the scan destinations of seatColumns
+func seatScanDest(i *Seat) []interface{} {
+	return []interface{}{
+		&i.ID,
+		&i.OrgID,
+		&i.SeatingPlanID,
+		&i.Num,
+		&i.Name,
+		&i.X,
+		&i.Y,
+		&i.OrderID,
+		&i.OrderItemID,
+		&i.SplitOrderID,
+		&i.PriceCategoryID,
+		&i.StatusCode,
+		&i.OfferedAt,
+		&i.SvgTransform,
+		&i.SvgPath,
+		&i.Wheelchair,
+		&i.OverrideNum,
+		&i.LinkedSeatID,
+		&i.Description,
+		&i.SeatRowID,
+		&i.SeatBlockID,
+		&i.SeatGateID,
+		&i.BestSeatGroupID,
+		&i.CreatedAt,
+		&i.CreatedByID,
+		&i.UpdatedAt,
+		&i.UpdatedByID,
+		&i.DeletedAt,
+		&i.DeletedByID,
+	}
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/proj/business/domain"
+)
+
+// createSeatLog writes the seat log entry with the changed seat fields
+func createSeatLog(ctx context.Context, tx *Queries, params InsertSeatLogParams, changes domain.SeatChanges) error {
+	if len(changes) > 0 {
+		rawChanges, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		params.Changes = rawChanges
+	}
+	return tx.InsertSeatLog(ctx, params)
+}
+
+// seatCreateChanges returns all fields of the created seat as changes from nil
+func seatCreateChanges(p InsertSeatParams) domain.SeatChanges {
+	changes := make(domain.SeatChanges)
+	changes.Add(domain.SeatFieldNum, nil, nullInt32Value(p.Num))
+	changes.Add(domain.SeatFieldName, nil, nullStringValue(p.Name))
+	changes.Add(domain.SeatFieldX, nil, nullInt32Value(p.X))
+	changes.Add(domain.SeatFieldY, nil, nullInt32Value(p.Y))
+	changes.Add(domain.SeatFieldSvgPath, nil, nullStringValue(p.SvgPath))
+	changes.Add(domain.SeatFieldSvgTransform, nil, nullStringValue(p.SvgTransform))
+	changes.Add(domain.SeatFieldPriceCategoryID, nil, nullStringValue(p.PriceCategoryID))
+	changes.Add(domain.SeatFieldStatusCode, nil, nullInt32Value(p.StatusCode))
+	changes.Add(domain.SeatFieldWheelchair, nil, nullBoolValue(p.Wheelchair))
+	changes.Add(domain.SeatFieldOverrideNum, nil, nullStringValue(p.OverrideNum))
+	changes.Add(domain.SeatFieldLinkedSeatID, nil, nullStringValue(p.LinkedSeatID))
+	changes.Add(domain.SeatFieldDescription, nil, nullStringValue(p.Description))
+	changes.Add(domain.SeatFieldBestSeatGroupID, nil, nullStringValue(p.BestSeatGroupID))
+	changes.Add(domain.SeatFieldSeatRowID, nil, nullStringValue(p.SeatRowID))
+	changes.Add(domain.SeatFieldSeatBlockID, nil, nullStringValue(p.SeatBlockID))
+	changes.Add(domain.SeatFieldSeatGateID, nil, nullStringValue(p.SeatGateID))
+	return changes
+}
+
+// seatUpdateChanges returns the fields set by the update which differ from the current seat
+func seatUpdateChanges(current Seat, p UpdateSeatParams) domain.SeatChanges {
+	changes := make(domain.SeatChanges)
+	if p.SetNum {
+		changes.Add(domain.SeatFieldNum, nullInt32Value(current.Num), p.Num)
+	}
+	if p.SetName {
+		changes.Add(domain.SeatFieldName, nullStringValue(current.Name), stringValue(p.Name))
+	}
+	if p.SetX {
+		changes.Add(domain.SeatFieldX, nullInt32Value(current.X), p.X)
+	}
+	if p.SetY {
+		changes.Add(domain.SeatFieldY, nullInt32Value(current.Y), p.Y)
+	}
+	if p.SetSvgPath {
+		changes.Add(domain.SeatFieldSvgPath, nullStringValue(current.SvgPath), stringValue(p.SvgPath))
+	}
+	if p.SetSvgTransform {
+		changes.Add(domain.SeatFieldSvgTransform, nullStringValue(current.SvgTransform), stringValue(p.SvgTransform))
+	}
+	if p.SetPriceCategoryIDToNull {
+		changes.Add(domain.SeatFieldPriceCategoryID, nullStringValue(current.PriceCategoryID), nil)
+	}
+	if p.SetPriceCategoryID {
+		changes.Add(domain.SeatFieldPriceCategoryID, nullStringValue(current.PriceCategoryID), stringValue(p.PriceCategoryID))
+	}
+	if p.SetStatusCode {
+		changes.Add(domain.SeatFieldStatusCode, nullInt32Value(current.StatusCode), p.StatusCode)
+	}
+	if p.SetSplitOrderID {
+		changes.Add(domain.SeatFieldSplitOrderID, nullStringValue(current.SplitOrderID), stringValue(p.SplitOrderID))
+	}
+	if p.SetWheelchair {
+		changes.Add(domain.SeatFieldWheelchair, nullBoolValue(current.Wheelchair), p.Wheelchair)
+	}
+	if p.SetOverrideNum {
+		changes.Add(domain.SeatFieldOverrideNum, nullStringValue(current.OverrideNum), stringValue(p.OverrideNum))
+	}
+	if p.SetLinkedSeatID {
+		changes.Add(domain.SeatFieldLinkedSeatID, nullStringValue(current.LinkedSeatID), stringValue(p.LinkedSeatID))
+	}
+	if p.SetDescription {
+		changes.Add(domain.SeatFieldDescription, nullStringValue(current.Description), stringValue(p.Description))
+	}
+	if p.SetBestSeatGroupID {
+		changes.Add(domain.SeatFieldBestSeatGroupID, nullStringValue(current.BestSeatGroupID), stringValue(p.BestSeatGroupID))
+	}
+	if p.SetSeatRowID {
+		changes.Add(domain.SeatFieldSeatRowID, nullStringValue(current.SeatRowID), stringValue(p.SeatRowID))
+	}
+	if p.SetSeatBlockID {
+		changes.Add(domain.SeatFieldSeatBlockID, nullStringValue(current.SeatBlockID), stringValue(p.SeatBlockID))
+	}
+	if p.SetSeatGateID {
+		changes.Add(domain.SeatFieldSeatGateID, nullStringValue(current.SeatGateID), stringValue(p.SeatGateID))
+	}
+	if p.SetOrderID {
+		changes.Add(domain.SeatFieldOrderID, nullStringValue(current.OrderID), stringValue(p.OrderID))
+	}
+	if p.SetOrderItemID {
+		changes.Add(domain.SeatFieldOrderItemID, nullStringValue(current.OrderItemID), stringValue(p.OrderItemID))
+	}
+	if p.SetOfferedAt {
+		changes.Add(domain.SeatFieldOfferedAt, nullTimeValue(current.OfferedAt), p.OfferedAt.UTC())
+	}
+	return changes
+}
+
+// seatStatusChanges returns the changes of a seat status update
+func seatStatusChanges(current LockSeatsForUpdateRow, status domain.UpdateSeatStatus) domain.SeatChanges {
+	changes := make(domain.SeatChanges)
+	changes.Add(domain.SeatFieldStatusCode, int32(lockedSeatStatus(current)), int32(status.StatusCode))
+	if status.AddOrderID != nil {
+		changes.Add(domain.SeatFieldOrderID, nullStringValue(current.OrderID), stringValue(*status.AddOrderID))
+		changes.Add(domain.SeatFieldOrderItemID, nullStringValue(current.OrderItemID), stringValue(*status.AddOrderItemID))
+	}
+	// removing the order clears its item as well
+	if status.RemoveOrderID {
+		changes.Add(domain.SeatFieldOrderID, nullStringValue(current.OrderID), nil)
+		changes.Add(domain.SeatFieldOrderItemID, nullStringValue(current.OrderItemID), nil)
+	}
+	if status.RemoveSplitOrderID {
+		changes.Add(domain.SeatFieldSplitOrderID, nullStringValue(current.SplitOrderID), nil)
+	}
+	return changes
+}
+
+// values are stored as nil when not set so empty strings and NULLs produce the same diff
+
+func stringValue(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullStringValue(s sql.NullString) interface{} {
+	if !s.Valid {
+		return nil
+	}
+	return stringValue(s.String)
+}
+
+func nullInt32Value(i sql.NullInt32) interface{} {
+	if !i.Valid {
+		return nil
+	}
+	return i.Int32
+}
+
+func nullBoolValue(b sql.NullBool) interface{} {
+	if !b.Valid {
+		return nil
+	}
+	return b.Bool
+}
+
+func nullTimeValue(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time.UTC()
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const insertSeatLog = `
+INSERT INTO seat_logs (id, org_id, seat_id, user_id, seating_plan_id, order_id, action, changes, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type InsertSeatLogParams struct {
+	ID            string
+	OrgID         string
+	SeatID        string
+	UserID        sql.NullString
+	SeatingPlanID sql.NullString
+	OrderID       sql.NullString
+	Action        sql.NullString
+	Changes       []byte
+	CreatedAt     time.Time
+}
+
+func (q *Queries) InsertSeatLog(ctx context.Context, arg InsertSeatLogParams) error {
+	_, err := q.db.ExecContext(ctx, insertSeatLog,
+		arg.ID,
+		arg.OrgID,
+		arg.SeatID,
+		arg.UserID,
+		arg.SeatingPlanID,
+		arg.OrderID,
+		arg.Action,
+		arg.Changes,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const getOrgSeatLogs = `
+SELECT sl.id, sl.seq, sl.seat_id, sl.user_id, sl.seating_plan_id, sl.order_id, sl.action, sl.changes, sl.created_at
+FROM seat_logs sl
+WHERE sl.org_id = $1
+ORDER BY sl.created_at, sl.seq
+LIMIT $2 OFFSET $3
+`
+
+type GetOrgSeatLogsParams struct {
+	OrgID  string
+	Limit  int64
+	Offset int64
+}
+
+func (q *Queries) GetOrgSeatLogs(ctx context.Context, arg GetOrgSeatLogsParams) ([]GetSeatLogsPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrgSeatLogs, arg.OrgID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanSeatLogsPageRows(rows)
+}
+
+// getSeatLogsPage orders logs of the same time by seq, the order in which they were written
+const getSeatLogsPage = `
+SELECT sl.id, sl.seq, sl.seat_id, sl.user_id, sl.seating_plan_id, sl.order_id, sl.action, sl.changes, sl.created_at
+FROM seat_logs sl
+WHERE sl.org_id = $1
+  AND (NOT $2::bool OR sl.seat_id = $3)
+  AND (NOT $4::bool OR sl.seating_plan_id = $5)
+  AND (NOT $6::bool OR sl.order_id = $7)
+  AND (NOT $8::bool OR sl.user_id = $9)
+  AND (NOT $10::bool OR sl.created_at >= $11)
+  AND (NOT $12::bool OR sl.created_at <= $13)
+  AND (NOT $14::bool OR (sl.created_at, sl.seq) > ($15::timestamp, $16::bigint))
+ORDER BY sl.created_at, sl.seq
+LIMIT $17
+`
+
+type GetSeatLogsPageParams struct {
+	OrgID           string
+	IsSeatID        bool
+	SeatID          string
+	IsSeatingPlanID bool
+	SeatingPlanID   string
+	IsOrderID       bool
+	OrderID         string
+	IsUserID        bool
+	UserID          string
+	IsCreatedFrom   bool
+	CreatedFrom     time.Time
+	IsCreatedTo     bool
+	CreatedTo       time.Time
+	IsAfter         bool
+	AfterCreatedAt  time.Time
+	AfterSeq        int64
+	Limit           int64
+}
+
+type GetSeatLogsPageRow struct {
+	ID            string
+	Seq           int64
+	SeatID        string
+	UserID        sql.NullString
+	SeatingPlanID sql.NullString
+	OrderID       sql.NullString
+	Action        sql.NullString
+	Changes       []byte
+	CreatedAt     time.Time
+}
+
+func (q *Queries) GetSeatLogsPage(ctx context.Context, arg GetSeatLogsPageParams) ([]GetSeatLogsPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSeatLogsPage,
+		arg.OrgID,
+		arg.IsSeatID,
+		arg.SeatID,
+		arg.IsSeatingPlanID,
+		arg.SeatingPlanID,
+		arg.IsOrderID,
+		arg.OrderID,
+		arg.IsUserID,
+		arg.UserID,
+		arg.IsCreatedFrom,
+		arg.CreatedFrom,
+		arg.IsCreatedTo,
+		arg.CreatedTo,
+		arg.IsAfter,
+		arg.AfterCreatedAt,
+		arg.AfterSeq,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanSeatLogsPageRows(rows)
+}
+
+func scanSeatLogsPageRows(rows *sql.Rows) ([]GetSeatLogsPageRow, error) {
+	defer rows.Close()
+	var items []GetSeatLogsPageRow
+	for rows.Next() {
+		var i GetSeatLogsPageRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.SeatID,
+			&i.UserID,
+			&i.SeatingPlanID,
+			&i.OrderID,
+			&i.Action,
+			&i.Changes,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSeatLogsBySeatIDs = `
+DELETE FROM seat_logs
+WHERE seat_id = ANY($1::varchar[])
+`
+
+func (q *Queries) DeleteSeatLogsBySeatIDs(ctx context.Context, seatIDs []string) error {
+	_, err := q.db.ExecContext(ctx, deleteSeatLogsBySeatIDs, pq.Array(seatIDs))
+	return err
+}
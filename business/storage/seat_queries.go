@@ -0,0 +1,356 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const lockSeatsForUpdate = `
+SELECT id, seating_plan_id, status_code, order_id, order_item_id, split_order_id, linked_seat_id, updated_at, deleted_at
+FROM seats
+WHERE org_id = $1 AND (id = ANY($2::varchar[]) OR order_item_id = ANY($3::varchar[]))
+ORDER BY id
+FOR UPDATE
+`
+
+type LockSeatsForUpdateParams struct {
+	OrgID        string
+	SeatIds      []string
+	OrderItemIds []string
+}
+
+type LockSeatsForUpdateRow struct {
+	ID            string
+	SeatingPlanID string
+	StatusCode    sql.NullInt32
+	OrderID       sql.NullString
+	OrderItemID   sql.NullString
+	SplitOrderID  sql.NullString
+	LinkedSeatID  sql.NullString
+	UpdatedAt     sql.NullTime
+	DeletedAt     sql.NullTime
+}
+
+// LockSeatsForUpdate locks the seat rows until the end of the transaction.
+// Rows are locked in id order so concurrent batches can't deadlock each other.
+func (q *Queries) LockSeatsForUpdate(ctx context.Context, arg LockSeatsForUpdateParams) ([]LockSeatsForUpdateRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockSeatsForUpdate, arg.OrgID, pq.Array(arg.SeatIds), pq.Array(arg.OrderItemIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockSeatsForUpdateRow
+	for rows.Next() {
+		var i LockSeatsForUpdateRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SeatingPlanID,
+			&i.StatusCode,
+			&i.OrderID,
+			&i.OrderItemID,
+			&i.SplitOrderID,
+			&i.LinkedSeatID,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSeatRowsForUpdate = `
+SELECT ` + seatColumns + `
+FROM seats
+WHERE org_id = $1 AND id = ANY($2::varchar[])
+ORDER BY id
+FOR UPDATE
+`
+
+// LockSeatRowsForUpdate locks the seat rows like LockSeatsForUpdate and returns all of their columns
+func (q *Queries) LockSeatRowsForUpdate(ctx context.Context, arg LockSeatsForUpdateParams) ([]Seat, error) {
+	return q.querySeats(ctx, lockSeatRowsForUpdate, arg.OrgID, pq.Array(arg.SeatIds))
+}
+
+const getOrgIDsWithOfferedSeats = `
+SELECT DISTINCT org_id
+FROM seats
+WHERE status_code = $1 AND offered_at IS NOT NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) GetOrgIDsWithOfferedSeats(ctx context.Context, offeredStatusCode int32) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getOrgIDsWithOfferedSeats, offeredStatusCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var orgID string
+		if err := rows.Scan(&orgID); err != nil {
+			return nil, err
+		}
+		items = append(items, orgID)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseOfferedSeats = `
+UPDATE seats
+SET status_code   = CASE WHEN COALESCE(seats.linked_seat_id, '') <> '' THEN $9 ELSE $3 END,
+    offered_at    = NULL,
+    updated_at    = $4,
+    updated_by_id = $5
+FROM seats AS old
+WHERE seats.id = old.id
+  AND seats.org_id = $1
+  AND seats.status_code = $2
+  AND seats.deleted_at IS NULL
+  AND (($6::bool AND seats.offered_at <= $7) OR seats.id = ANY($8::varchar[]))
+  AND NOT EXISTS (SELECT 1 FROM seat_holds WHERE seat_holds.seat_id = seats.id)
+RETURNING seats.id, seats.seating_plan_id, seats.status_code, old.offered_at, seats.linked_seat_id
+`
+
+type ReleaseOfferedSeatsParams struct {
+	OrgID               string
+	OfferedStatusCode   int32
+	AvailableStatusCode int32
+	UpdatedAt           time.Time
+	UpdatedByID         sql.NullString
+	IsOfferedBefore     bool
+	OfferedBefore       time.Time
+	SeatIds             []string
+	LockedStatusCode    int32
+}
+
+type ReleaseOfferedSeatsRow struct {
+	ID            string
+	SeatingPlanID string
+	StatusCode    int32
+	OfferedAt     sql.NullTime
+	LinkedSeatID  sql.NullString
+}
+
+// ReleaseOfferedSeats makes offered seats available again, either the ones offered before
+// OfferedBefore or the ones listed in SeatIds. Seats under a hold are left to the hold expiry,
+// linked seats are locked again instead.
+func (q *Queries) ReleaseOfferedSeats(ctx context.Context, arg ReleaseOfferedSeatsParams) ([]ReleaseOfferedSeatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, releaseOfferedSeats,
+		arg.OrgID,
+		arg.OfferedStatusCode,
+		arg.AvailableStatusCode,
+		arg.UpdatedAt,
+		arg.UpdatedByID,
+		arg.IsOfferedBefore,
+		arg.OfferedBefore,
+		pq.Array(arg.SeatIds),
+		arg.LockedStatusCode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReleaseOfferedSeatsRow
+	for rows.Next() {
+		var i ReleaseOfferedSeatsRow
+		if err := rows.Scan(&i.ID, &i.SeatingPlanID, &i.StatusCode, &i.OfferedAt, &i.LinkedSeatID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreSeats = `
+UPDATE seats
+SET deleted_at    = NULL,
+    deleted_by_id = NULL,
+    updated_at    = $3,
+    updated_by_id = $4
+FROM seats AS old
+WHERE seats.id = old.id
+  AND seats.org_id = $1
+  AND seats.id = ANY($2::varchar[])
+  AND seats.deleted_at IS NOT NULL
+RETURNING seats.id, seats.seating_plan_id, seats.order_id, old.deleted_at
+`
+
+type RestoreSeatsParams struct {
+	OrgID       string
+	SeatIds     []string
+	UpdatedAt   time.Time
+	UpdatedByID sql.NullString
+}
+
+type RestoreSeatsRow struct {
+	ID            string
+	SeatingPlanID string
+	OrderID       sql.NullString
+	DeletedAt     sql.NullTime
+}
+
+func (q *Queries) RestoreSeats(ctx context.Context, arg RestoreSeatsParams) ([]RestoreSeatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, restoreSeats, arg.OrgID, pq.Array(arg.SeatIds), arg.UpdatedAt, arg.UpdatedByID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RestoreSeatsRow
+	for rows.Next() {
+		var i RestoreSeatsRow
+		if err := rows.Scan(&i.ID, &i.SeatingPlanID, &i.OrderID, &i.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// lockDeletedSeatsBefore tells as well whether the seats were ever sold to an order according to their logs
+const lockDeletedSeatsBefore = `
+SELECT id, order_id, order_item_id, split_order_id,
+       EXISTS (SELECT 1 FROM seat_logs WHERE seat_logs.seat_id = seats.id AND seat_logs.order_id IS NOT NULL)
+FROM seats
+WHERE org_id = $1 AND deleted_at IS NOT NULL AND deleted_at <= $2
+ORDER BY id
+FOR UPDATE
+`
+
+type LockDeletedSeatsBeforeParams struct {
+	OrgID         string
+	DeletedBefore time.Time
+}
+
+type LockDeletedSeatsBeforeRow struct {
+	ID           string
+	OrderID      sql.NullString
+	OrderItemID  sql.NullString
+	SplitOrderID sql.NullString
+	HasOrderLogs bool
+}
+
+func (q *Queries) LockDeletedSeatsBefore(ctx context.Context, arg LockDeletedSeatsBeforeParams) ([]LockDeletedSeatsBeforeRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockDeletedSeatsBefore, arg.OrgID, arg.DeletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockDeletedSeatsBeforeRow
+	for rows.Next() {
+		var i LockDeletedSeatsBeforeRow
+		if err := rows.Scan(&i.ID, &i.OrderID, &i.OrderItemID, &i.SplitOrderID, &i.HasOrderLogs); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLiveSeatsLinkedTo = `
+SELECT id, linked_seat_id, status_code
+FROM seats
+WHERE org_id = $1 AND linked_seat_id = ANY($2::varchar[]) AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE
+`
+
+type LockLiveSeatsLinkedToParams struct {
+	OrgID   string
+	SeatIds []string
+}
+
+type LockLiveSeatsLinkedToRow struct {
+	ID           string
+	LinkedSeatID string
+	StatusCode   sql.NullInt32
+}
+
+// LockLiveSeatsLinkedTo locks the seats which are not deleted and link to the given seats
+func (q *Queries) LockLiveSeatsLinkedTo(ctx context.Context, arg LockLiveSeatsLinkedToParams) ([]LockLiveSeatsLinkedToRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockLiveSeatsLinkedTo, arg.OrgID, pq.Array(arg.SeatIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockLiveSeatsLinkedToRow
+	for rows.Next() {
+		var i LockLiveSeatsLinkedToRow
+		if err := rows.Scan(&i.ID, &i.LinkedSeatID, &i.StatusCode); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unlinkDeletedSeatsFrom = `
+UPDATE seats
+SET linked_seat_id = NULL
+WHERE org_id = $1 AND linked_seat_id = ANY($2::varchar[]) AND deleted_at IS NOT NULL
+`
+
+type UnlinkDeletedSeatsFromParams struct {
+	OrgID   string
+	SeatIds []string
+}
+
+// UnlinkDeletedSeatsFrom clears links of deleted seats pointing to the given seats
+func (q *Queries) UnlinkDeletedSeatsFrom(ctx context.Context, arg UnlinkDeletedSeatsFromParams) error {
+	_, err := q.db.ExecContext(ctx, unlinkDeletedSeatsFrom, arg.OrgID, pq.Array(arg.SeatIds))
+	return err
+}
+
+const purgeSeats = `
+DELETE FROM seats
+WHERE org_id = $1 AND id = ANY($2::varchar[]) AND deleted_at IS NOT NULL
+`
+
+type PurgeSeatsParams struct {
+	OrgID   string
+	SeatIds []string
+}
+
+func (q *Queries) PurgeSeats(ctx context.Context, arg PurgeSeatsParams) error {
+	_, err := q.db.ExecContext(ctx, purgeSeats, arg.OrgID, pq.Array(arg.SeatIds))
+	return err
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/proj/business/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetSeatScoringConfig returns the best seats scoring config of the seating plan ids.SplID,
+// seating plans without config get the default one
+func (s *Storage) GetSeatScoringConfig(ctx context.Context, ids *domain.IDs) (*domain.SeatScoringConfig, error) {
+	row, err := s.queries.GetSeatScoringConfig(ctx, GetSeatScoringConfigParams{OrgID: ids.OrgID, SeatingPlanID: ids.SplID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.DefaultSeatScoringConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query seat scoring config: %w", err)
+	}
+	config := &domain.SeatScoringConfig{
+		DistanceWeight:   row.DistanceWeight,
+		CentralityWeight: row.CentralityWeight,
+		AisleWeight:      row.AisleWeight,
+		RowWeight:        row.RowWeight,
+		BlockWeight:      row.BlockWeight,
+		OffsetWeight:     row.OffsetWeight,
+	}
+	if err := json.Unmarshal(row.ReferencePoints, &config.ReferencePoints); err != nil {
+		return nil, fmt.Errorf("unmarshal seat scoring reference points: %w", err)
+	}
+	if err := json.Unmarshal(row.RowPreferences, &config.RowPreferences); err != nil {
+		return nil, fmt.Errorf("unmarshal seat scoring row preferences: %w", err)
+	}
+	if err := json.Unmarshal(row.BlockBiases, &config.BlockBiases); err != nil {
+		return nil, fmt.Errorf("unmarshal seat scoring block biases: %w", err)
+	}
+	return config, nil
+}
+
+// SetSeatScoringConfig sets the best seats scoring config of the seating plan ids.SplID
+func (s *Storage) SetSeatScoringConfig(ctx context.Context, ids *domain.IDs, config *domain.SeatScoringConfig, t time.Time) error {
+	ctx, span := s.tracer.Start(ctx, "storage.SetSeatScoringConfig")
+	span.SetAttributes(
+		attribute.Key("ids").String(spew.Sdump(ids)),
+		attribute.Key("config").String(spew.Sdump(config)),
+	)
+	defer span.End()
+
+	referencePoints := config.ReferencePoints
+	if referencePoints == nil {
+		referencePoints = []domain.SeatReferencePoint{}
+	}
+	rawReferencePoints, err := json.Marshal(referencePoints)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("marshal seat scoring reference points: %w", err)
+	}
+	rowPreferences, err := marshalSeatScores(config.RowPreferences)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("marshal seat scoring row preferences: %w", err)
+	}
+	blockBiases, err := marshalSeatScores(config.BlockBiases)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("marshal seat scoring block biases: %w", err)
+	}
+	upserted, err := s.queries.UpsertSeatScoringConfig(ctx, UpsertSeatScoringConfigParams{
+		SeatingPlanID:    ids.SplID,
+		OrgID:            ids.OrgID,
+		DistanceWeight:   config.DistanceWeight,
+		ReferencePoints:  rawReferencePoints,
+		CentralityWeight: config.CentralityWeight,
+		AisleWeight:      config.AisleWeight,
+		RowWeight:        config.RowWeight,
+		RowPreferences:   rowPreferences,
+		BlockWeight:      config.BlockWeight,
+		BlockBiases:      blockBiases,
+		OffsetWeight:     config.OffsetWeight,
+		CreatedAt:        t,
+		CreatedByID:      ids.UserID,
+		UpdatedByID:      nullString(ids.UserID),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("upsert seat scoring config: %w", err)
+	}
+	// the seating plan belongs to another org or doesn't exist
+	if upserted == 0 {
+		span.RecordError(domain.ErrSeatScoringPlanNotFound)
+		return domain.ErrSeatScoringPlanNotFound
+	}
+
+	return nil
+}
+
+func marshalSeatScores(scores map[string]float64) (json.RawMessage, error) {
+	if scores == nil {
+		scores = map[string]float64{}
+	}
+	return json.Marshal(scores)
+}
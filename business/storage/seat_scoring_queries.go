@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type SeatScoringConfig struct {
+	SeatingPlanID    string
+	OrgID            string
+	DistanceWeight   float64
+	ReferencePoints  json.RawMessage
+	CentralityWeight float64
+	AisleWeight      float64
+	RowWeight        float64
+	RowPreferences   json.RawMessage
+	BlockWeight      float64
+	BlockBiases      json.RawMessage
+	OffsetWeight     float64
+}
+
+const getSeatScoringConfig = `
+SELECT seating_plan_id, org_id, distance_weight, reference_points, centrality_weight, aisle_weight, row_weight, row_preferences,
+       block_weight, block_biases, offset_weight
+FROM seat_scoring_configs
+WHERE org_id = $1 AND seating_plan_id = $2
+`
+
+type GetSeatScoringConfigParams struct {
+	OrgID         string
+	SeatingPlanID string
+}
+
+func (q *Queries) GetSeatScoringConfig(ctx context.Context, arg GetSeatScoringConfigParams) (SeatScoringConfig, error) {
+	row := q.db.QueryRowContext(ctx, getSeatScoringConfig, arg.OrgID, arg.SeatingPlanID)
+	var i SeatScoringConfig
+	err := row.Scan(
+		&i.SeatingPlanID,
+		&i.OrgID,
+		&i.DistanceWeight,
+		&i.ReferencePoints,
+		&i.CentralityWeight,
+		&i.AisleWeight,
+		&i.RowWeight,
+		&i.RowPreferences,
+		&i.BlockWeight,
+		&i.BlockBiases,
+		&i.OffsetWeight,
+	)
+	return i, err
+}
+
+const upsertSeatScoringConfig = `
+INSERT INTO seat_scoring_configs (seating_plan_id, org_id, distance_weight, reference_points, centrality_weight,
+                                  aisle_weight, row_weight, row_preferences, block_weight, block_biases, offset_weight,
+                                  created_at, created_by_id)
+SELECT $1::varchar, $2::varchar, $3::double precision, $4::jsonb, $5::double precision, $6::double precision,
+       $7::double precision, $8::jsonb, $9::double precision, $10::jsonb, $11::double precision, $12::timestamp,
+       $13::varchar
+WHERE EXISTS (SELECT 1 FROM seats WHERE seating_plan_id = $1 AND org_id = $2)
+ON CONFLICT (seating_plan_id) DO UPDATE
+SET distance_weight = EXCLUDED.distance_weight,
+    reference_points = EXCLUDED.reference_points,
+    centrality_weight = EXCLUDED.centrality_weight,
+    aisle_weight = EXCLUDED.aisle_weight,
+    row_weight = EXCLUDED.row_weight,
+    row_preferences = EXCLUDED.row_preferences,
+    block_weight = EXCLUDED.block_weight,
+    block_biases = EXCLUDED.block_biases,
+    offset_weight = EXCLUDED.offset_weight,
+    updated_at = $12,
+    updated_by_id = $14
+WHERE seat_scoring_configs.org_id = EXCLUDED.org_id
+`
+
+type UpsertSeatScoringConfigParams struct {
+	SeatingPlanID    string
+	OrgID            string
+	DistanceWeight   float64
+	ReferencePoints  json.RawMessage
+	CentralityWeight float64
+	AisleWeight      float64
+	RowWeight        float64
+	RowPreferences   json.RawMessage
+	BlockWeight      float64
+	BlockBiases      json.RawMessage
+	OffsetWeight     float64
+	CreatedAt        time.Time
+	CreatedByID      string
+	UpdatedByID      sql.NullString
+}
+
+// UpsertSeatScoringConfig returns the number of inserted or updated configs, nothing is written when the seating plan
+// has no seats of the org or when its config belongs to another org
+func (q *Queries) UpsertSeatScoringConfig(ctx context.Context, arg UpsertSeatScoringConfigParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertSeatScoringConfig,
+		arg.SeatingPlanID,
+		arg.OrgID,
+		arg.DistanceWeight,
+		arg.ReferencePoints,
+		arg.CentralityWeight,
+		arg.AisleWeight,
+		arg.RowWeight,
+		arg.RowPreferences,
+		arg.BlockWeight,
+		arg.BlockBiases,
+		arg.OffsetWeight,
+		arg.CreatedAt,
+		arg.CreatedByID,
+		arg.UpdatedByID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
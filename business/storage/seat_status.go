@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/proj/business/domain"
+)
+
+// validateSeatsStatusTransition locks the seats and checks that all of them may change their status to the given one,
+// it returns the locked seats by seat id
+func validateSeatsStatusTransition(ctx context.Context, tx *Queries, orgID string, seatIDs, orderItemIDs []string, to domain.SeatStatus, refund bool) (map[string]LockSeatsForUpdateRow, error) {
+	lockedSeats, err := tx.LockSeatsForUpdate(ctx, LockSeatsForUpdateParams{OrgID: orgID, SeatIds: seatIDs, OrderItemIds: orderItemIDs})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLockedSeats(seatIDs, lockedSeats); err != nil {
+		return nil, err
+	}
+	lockedSeatsByID := make(map[string]LockSeatsForUpdateRow, len(lockedSeats))
+	for _, ls := range lockedSeats {
+		// seats locked by a link change their status together with their group
+		if err := domain.ValidateSeatStatusTransition(ls.ID, lockedSeatStatus(ls), to, refund, ls.LinkedSeatID.String != ""); err != nil {
+			return nil, err
+		}
+		lockedSeatsByID[ls.ID] = ls
+	}
+	return lockedSeatsByID, nil
+}
+
+// lockSeats locks the seats and returns their current rows by seat id
+func lockSeats(ctx context.Context, tx *Queries, orgID string, seatIDs []string) (map[string]Seat, error) {
+	seats, err := tx.LockSeatRowsForUpdate(ctx, LockSeatsForUpdateParams{OrgID: orgID, SeatIds: seatIDs})
+	if err != nil {
+		return nil, err
+	}
+	lockedSeats := make([]LockSeatsForUpdateRow, len(seats))
+	seatsByID := make(map[string]Seat, len(seats))
+	for i, seat := range seats {
+		lockedSeats[i] = LockSeatsForUpdateRow{ID: seat.ID, DeletedAt: seat.DeletedAt}
+		seatsByID[seat.ID] = seat
+	}
+	if err := checkLockedSeats(seatIDs, lockedSeats); err != nil {
+		return nil, err
+	}
+	return seatsByID, nil
+}
+
+// lockedSeatStatus returns the seat status, seats without status are treated as available like on creation
+func lockedSeatStatus(ls LockSeatsForUpdateRow) domain.SeatStatus {
+	if !ls.StatusCode.Valid || ls.StatusCode.Int32 == 0 {
+		return domain.SeatStatusAvailable
+	}
+	return domain.SeatStatus(ls.StatusCode.Int32)
+}
+
+// linkLockedSeat tells whether the seat is locked because it is linked to another seat,
+// such seats are locked again instead of becoming available
+func linkLockedSeat(ls LockSeatsForUpdateRow) bool {
+	return ls.LinkedSeatID.String != "" && lockedSeatStatus(ls) == domain.SeatStatusLocked
+}
+
+// linkedSeatIDs returns ids of the locked seats which are linked to other seats
+func linkedSeatIDs(lockedSeats map[string]LockSeatsForUpdateRow) []string {
+	var seatIDs []string
+	for _, ls := range lockedSeats {
+		if ls.LinkedSeatID.String != "" {
+			seatIDs = append(seatIDs, ls.ID)
+		}
+	}
+	return seatIDs
+}